@@ -3,68 +3,192 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"github.com/othersidedrl/portfolio/backend/internal/apikey"
+	"github.com/othersidedrl/portfolio/backend/internal/archive"
 	"github.com/othersidedrl/portfolio/backend/internal/auth"
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
 	"github.com/othersidedrl/portfolio/backend/internal/config"
 	"github.com/othersidedrl/portfolio/backend/internal/database"
+	"github.com/othersidedrl/portfolio/backend/internal/draft"
 	"github.com/othersidedrl/portfolio/backend/internal/hero"
 	"github.com/othersidedrl/portfolio/backend/internal/image"
+	"github.com/othersidedrl/portfolio/backend/internal/lockout"
 	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/oidc"
 	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
 	"github.com/othersidedrl/portfolio/backend/internal/server"
+	"github.com/othersidedrl/portfolio/backend/internal/session"
 	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"github.com/othersidedrl/portfolio/backend/internal/trash"
+	"github.com/othersidedrl/portfolio/backend/internal/user"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
 )
 
 func main() {
-	// 1. Load Configuration
+	// 1. Initialize Logger
+	logger.Init()
+
+	// `api migrate ...` manages the schema and `api seed ...` loads fixture
+	// content; both exit without serving and need only the database settings
+	if len(os.Args) > 1 && (os.Args[1] == "migrate" || os.Args[1] == "seed") {
+		cfg, err := config.LoadDatabase()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load configuration:", err)
+			os.Exit(1)
+		}
+		if os.Args[1] == "migrate" {
+			os.Exit(runMigrate(cfg, os.Args[2:]))
+		}
+		os.Exit(runSeed(cfg, os.Args[2:]))
+	}
+
+	// 2. Load Configuration
 	cfg, err := config.Load()
 	if err != nil {
 		panic("Failed to load configuration: " + err.Error())
 	}
 
-	// 2. Initialize Logger
-	logger.Init()
 	logger.Info("Starting application...")
 
-	// 3. Connect to Database (AutoMigrate running here)
-	db := database.ConnectDB(cfg)
+	// Cancelled on SIGINT or SIGTERM, which also stops waiting for the
+	// database and Redis to come up
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 3. Connect to Database (pending migrations are applied here)
+	db, err := database.ConnectDB(ctx, cfg)
+	if err != nil {
+		logger.Error("Failed to connect to DB", "error", err)
+		os.Exit(1)
+	}
 
-	// 4. Initialize Redis
-	utils.InitRedis(cfg)
+	// 4. Initialize Redis if CACHE_BACKEND uses it. Sign-in, sessions, the
+	// login lockout and OIDC then keep their state there too, so the API
+	// does not start without it; after a later outage the client
+	// reconnects, and meanwhile the response cache falls back to memory if
+	// CACHE_BACKEND allows it. Otherwise that state stays in the memory of
+	// this instance, which suits a single instance only.
+	useRedis := cache.UsesRedis(cfg.CacheBackend)
+	if useRedis {
+		if err := utils.InitRedis(ctx, cfg); err != nil {
+			if ctx.Err() == nil {
+				logger.Error("Redis is unavailable, and authentication requires it with this cache backend",
+					"cache_backend", cfg.CacheBackend, "error", err)
+			}
+			utils.CloseRedis()
+			database.Close(db)
+			os.Exit(1)
+		}
+	} else {
+		logger.Info("Keeping sign-in state in memory, as the cache backend does not use Redis",
+			"cache_backend", cfg.CacheBackend)
+	}
+	cacheStore, err := cache.NewStore(cfg, utils.RedisClient)
+	if err != nil {
+		logger.Error("Failed to create the response cache", "error", err)
+		utils.CloseRedis()
+		database.Close(db)
+		os.Exit(1)
+	}
 
 	// 5. Initialize Services
 	// Utils
-	jwtService := utils.NewJWTService(cfg.JWTSecret)
+	keyStore, err := utils.LoadKeyStore(cfg.JWTSigningKeyFile, cfg.JWTVerificationKeyFiles, cfg.JWTSecret, cfg.JWTSecretAcceptedUntil)
+	if err != nil {
+		logger.Error("Failed to load JWT keys", "error", err)
+		utils.CloseRedis()
+		database.Close(db)
+		os.Exit(1)
+	}
+	jwtService := utils.NewJWTService(keyStore, cfg.AccessTokenTTL)
+
+	// Sign-in state, kept in Redis when the API uses it
+	lockoutPolicy := lockout.Policy{
+		MaxFailures:     cfg.LoginMaxFailures,
+		Window:          cfg.LoginFailureWindow,
+		LockoutDuration: cfg.LoginLockoutDuration,
+	}
+	var (
+		tokenStore   token.Store
+		lockoutStore lockout.Store
+		oidcStates   oidc.StateStore
+	)
+	if useRedis {
+		tokenStore = token.NewRedisStore(utils.RedisClient, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+		lockoutStore = lockout.NewRedisStore(utils.RedisClient, lockoutPolicy)
+		oidcStates = oidc.NewRedisStateStore(utils.RedisClient)
+	} else {
+		tokenStore = token.NewMemoryStore(cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+		lockoutStore = lockout.NewMemoryStore(lockoutPolicy)
+		oidcStates = oidc.NewMemoryStateStore()
+	}
+
+	// Users
+	userRepo := user.NewGormUserRepository(db)
+	userService := user.NewService(userRepo, cfg.TOTPIssuer)
+	userHandler := user.NewHandler(userService)
+
+	// API Keys
+	apiKeyRepo := apikey.NewGormAPIKeyRepository(db)
+	apiKeyService := apikey.NewService(apiKeyRepo, userService)
+	apiKeyHandler := apikey.NewHandler(apiKeyService)
 
 	// Auth
-	authService := auth.NewService(jwtService, cfg)
-	authHandler := auth.NewHandler(authService)
+	lockoutHandler := lockout.NewHandler(lockoutStore)
+	sessionRepo := session.NewGormSessionRepository(db)
+	sessionService := session.NewService(sessionRepo, tokenStore, cfg.RefreshTokenTTL)
+	sessionHandler := session.NewHandler(sessionService)
+	authService := auth.NewService(jwtService, tokenStore, userService, lockoutStore, sessionService)
+
+	// External identity providers
+	oidcClient := &http.Client{Timeout: 10 * time.Second}
+	oidcProviders := make([]*oidc.Provider, 0, len(cfg.OIDCProviders))
+	for _, providerCfg := range cfg.OIDCProviders {
+		oidcProviders = append(oidcProviders, oidc.NewProvider(providerCfg, oidcClient))
+	}
+	oidcService := oidc.NewService(oidcProviders, oidcStates, userService, tokenStore)
+	oidcHandler := oidc.NewHandler(oidcService, cfg.OIDCLoginRedirectURL)
+	authHandler := auth.NewHandler(authService, auth.CookieConfig{
+		Enabled:    cfg.CookieAuth,
+		Domain:     cfg.CookieDomain,
+		Secure:     cfg.CookieSecure,
+		SameSite:   auth.ParseSameSite(cfg.CookieSameSite),
+		RefreshTTL: cfg.RefreshTokenTTL,
+	})
+
+	// Content writes drop the cached public responses showing them
+	invalidate := cache.NewInvalidator(cacheStore)
 
 	// Hero
 	heroRepo := hero.NewGormHeroRepository(db)
-	heroService := hero.NewService(heroRepo)
+	heroService := hero.NewService(heroRepo, invalidate)
 	heroHandler := hero.NewHandler(heroService)
 
 	// About
 	aboutRepo := about.NewGormAboutRepository(db)
-	aboutService := about.NewService(aboutRepo)
+	aboutService := about.NewService(aboutRepo, invalidate)
 	aboutHandler := about.NewHandler(aboutService)
 
 	// Testimony
 	testimonyRepo := testimony.NewGormTestimonyRepository(db)
-	testimonyService := testimony.NewService(testimonyRepo, cfg)
+	testimonyService := testimony.NewService(testimonyRepo, cfg, invalidate)
 	testimonyHandler := testimony.NewHandler(testimonyService)
 
 	// Project
 	projectRepo := project.NewGormProjectRepository(db)
-	projectService := project.NewService(projectRepo)
+	projectService := project.NewService(projectRepo, invalidate)
 	projectHandler := project.NewHandler(projectService)
 
 	// Image
@@ -75,36 +199,107 @@ func main() {
 	}
 	imageHandler := image.NewHandler(imageService)
 
+	// Trash
+	trashRepo := trash.NewGormTrashRepository(db)
+	trashService := trash.NewService(trashRepo, cfg.TrashRetention, invalidate)
+	trashHandler := trash.NewHandler(trashService)
+
+	// Revisions
+	revisionRepo := revision.NewGormRevisionRepository(db)
+	revisionService := revision.NewService(revisionRepo, invalidate)
+	revisionHandler := revision.NewHandler(revisionService)
+
+	// Export / Import
+	archiveRepo := archive.NewGormArchiveRepository(db)
+	archiveService := archive.NewService(archiveRepo, invalidate)
+	archiveHandler := archive.NewHandler(archiveService)
+
+	// Drafts
+	draftRepo := draft.NewGormDraftRepository(db)
+	draftService := draft.NewService(draftRepo, jwtService, cfg.PreviewTokenTTL, invalidate)
+	draftHandler := draft.NewHandler(draftService)
+
+	// Background work runs until shutdown, which waits for the current run
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	if tiered, ok := cacheStore.(*cache.TieredStore); ok {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			tiered.Run(workersCtx)
+		}()
+	}
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		trashService.RunRetention(workersCtx, time.Hour)
+	}()
+	go func() {
+		defer workers.Done()
+		draftService.RunScheduler(workersCtx, cfg.DraftSchedulerInterval)
+	}()
+
 	// 6. Setup Router & Server
-	router := server.NewRouter(cfg, authHandler, heroHandler, aboutHandler, testimonyHandler, projectHandler, imageHandler, jwtService)
+	router := server.NewRouter(cfg, authHandler, heroHandler, aboutHandler, testimonyHandler, projectHandler, imageHandler, userHandler, apiKeyHandler, lockoutHandler, sessionHandler, oidcHandler, trashHandler, archiveHandler, revisionHandler, draftHandler, jwtService, tokenStore, apiKeyService, sessionService, version.NewStore(db), cacheStore)
 	srv := server.StartServer(":"+cfg.Port, router)
 
 	// 7. Start Server with Graceful Shutdown
+	serverErr := make(chan error, 1)
 	go func() {
 		logger.Info("Server running", "port", cfg.Port)
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logger.Error("Server failed to start", "error", err)
-			os.Exit(1)
+			serverErr <- err
 		}
 	}()
 
-	// Wait for interrupt signal using channel
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
-	logger.Info("Shutting down server...")
+	// Wait for an interrupt signal, or for the server to fail
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutting down server...")
+	case err := <-serverErr:
+		logger.Error("Server failed to start", "error", err)
+		exitCode = 1
+	}
+	stop()
 
-	// Context with timeout for cleanup
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Everything below shares one deadline
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	// Stop taking requests and let the ones in flight finish, then stop the
+	// background work, and only then close what both of them use
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
-
-	// Wait for DB close (if supported) or other cleanup
-	// sqlDB, _ := db.DB()
-	// sqlDB.Close()
+	stopWorkers()
+	if !waitFor(shutdownCtx, &workers) {
+		logger.Warn("Background work did not stop in time")
+	}
+	if err := utils.CloseRedis(); err != nil {
+		logger.Error("Failed to close Redis", "error", err)
+	}
+	if err := database.Close(db); err != nil {
+		logger.Error("Failed to close DB", "error", err)
+	}
 
 	logger.Info("Server exiting")
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// waitFor waits for wg until ctx is done and reports whether it finished
+func waitFor(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
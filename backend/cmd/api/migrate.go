@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/database"
+)
+
+const migrateUsage = "usage: api migrate up | down [steps] | status"
+
+// runMigrate handles `api migrate ...` and returns the process exit code
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.Connect(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to DB:", err)
+		return 1
+	}
+	defer database.Close(db)
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load migrations:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Migration failed:", err)
+			return 1
+		}
+		fmt.Printf("Applied %d migration(s)\n", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+		n, err := migrator.Down(ctx, steps)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Rollback failed:", err)
+			return 1
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", n)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to read migration status:", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/othersidedrl/portfolio/backend/internal/archive"
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/database"
+	"github.com/othersidedrl/portfolio/backend/internal/seed"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+const seedUsage = "usage: api seed [-dry-run] [fixture.yaml|fixture.json]"
+
+// runSeed handles `api seed ...` and returns the process exit code. Without
+// a file it loads the builtin demo portfolio. Pending migrations are applied
+// first unless DB_AUTO_MIGRATE is off, and the API's cached responses are
+// dropped afterwards.
+func runSeed(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintln(os.Stderr, seedUsage) }
+	dryRun := flags.Bool("dry-run", false, "report changes without saving them")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 {
+		if flags.NArg() > 1 {
+			flags.Usage()
+		}
+		return 2
+	}
+
+	var (
+		fixture *archive.ArchiveDto
+		err     error
+	)
+	if path := flags.Arg(0); path != "" {
+		fixture, err = seed.Load(path)
+	} else {
+		fixture, err = seed.Builtin(seed.Demo)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load fixture:", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.Connect(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to DB:", err)
+		return 1
+	}
+	defer database.Close(db)
+
+	// A fresh database needs its schema before it can take content
+	if cfg.DBAutoMigrate {
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load migrations:", err)
+			return 1
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			fmt.Fprintln(os.Stderr, "Migration failed:", err)
+			return 1
+		}
+	}
+
+	invalidate := cache.Nop
+	if !*dryRun {
+		var closeCache func()
+		invalidate, closeCache = seedInvalidator(ctx, cfg)
+		defer closeCache()
+	}
+
+	result, err := seed.Apply(ctx, db, fixture, *dryRun, invalidate)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Seeding failed:", err)
+		return 1
+	}
+
+	if result.DryRun {
+		fmt.Println("Dry run, nothing was saved")
+	}
+	fmt.Printf("Pages: %d\n", len(result.Pages))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SECTION\tCREATED\tUPDATED")
+	for _, s := range []struct {
+		name   string
+		counts archive.ImportCountsDto
+	}{
+		{"skills", result.Skills},
+		{"careers", result.Careers},
+		{"testimonies", result.Testimonies},
+		{"projects", result.Projects},
+	} {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", s.name, s.counts.Created, s.counts.Updated)
+	}
+	w.Flush()
+	return 0
+}
+
+// seedInvalidator invalidates the response caches the API keeps in Redis.
+// Caches kept in the memory of an API instance cannot be reached from here,
+// so they serve the old content until their entries expire.
+func seedInvalidator(ctx context.Context, cfg *config.Config) (cache.Invalidator, func()) {
+	if !cache.UsesRedis(cfg.CacheBackend) {
+		if cfg.CacheBackend != cache.BackendNone {
+			fmt.Fprintf(os.Stderr, "CACHE_BACKEND=%s caches in the API's memory; restart it or wait %s for the seeded content to show\n", cfg.CacheBackend, cfg.CachePageTTL)
+		}
+		return cache.Nop, func() {}
+	}
+
+	if err := utils.InitRedis(ctx, cfg); err != nil {
+		utils.CloseRedis()
+		fmt.Fprintf(os.Stderr, "Redis is unavailable, cached responses expire within %s: %v\n", cfg.CachePageTTL, err)
+		return cache.Nop, func() {}
+	}
+	store, err := cache.NewStore(cfg, utils.RedisClient)
+	if err != nil {
+		utils.CloseRedis()
+		fmt.Fprintln(os.Stderr, "Failed to create the response cache:", err)
+		return cache.Nop, func() {}
+	}
+	return cache.NewInvalidator(store), func() { utils.CloseRedis() }
+}
@@ -2,12 +2,15 @@ package about
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"gorm.io/gorm"
 )
 
 type Handler struct {
@@ -29,15 +32,26 @@ func (h *Handler) GetAboutPage(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(about)
 }
 
+// UpdateAboutPage applies a JSON merge patch to the about page. Cards are an
+// array, so a patch that sets them replaces all of them.
 func (h *Handler) UpdateAboutPage(w http.ResponseWriter, r *http.Request) {
-	var body AboutPageDto
+	body, err := h.service.Find(r.Context())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		body = &AboutPageDto{}
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	if err := utils.DecodeBody(r, &body); err != nil {
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.Update(r.Context(), body); err != nil {
+	if err := h.service.Update(r.Context(), *body); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -68,6 +82,27 @@ func (h *Handler) GetTechnicalSkills(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *Handler) GetTechnicalSkill(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid skill ID", http.StatusBadRequest)
+		return
+	}
+
+	skill, err := h.service.GetTechnicalSkillByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Skill not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(skill)
+}
+
 func (h *Handler) CreateTechnicalSkill(w http.ResponseWriter, r *http.Request) {
 	var body SkillItemDto
 
@@ -77,6 +112,10 @@ func (h *Handler) CreateTechnicalSkill(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.CreateTechnicalSkill(r.Context(), body); err != nil {
+		if errors.Is(err, ErrInvalidSkill) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -86,6 +125,7 @@ func (h *Handler) CreateTechnicalSkill(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Successfuly created a skill"})
 }
 
+// UpdateTechnicalSkill applies a JSON merge patch to a skill
 func (h *Handler) UpdateTechnicalSkill(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
@@ -93,14 +133,29 @@ func (h *Handler) UpdateTechnicalSkill(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var body SkillItemDto
+	body, err := h.service.GetTechnicalSkillByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Skill not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	if err := utils.DecodeBody(r, &body); err != nil {
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.UpdateTechnicalSkill(r.Context(), body, uint(id)); err != nil {
+	if err := h.service.UpdateTechnicalSkill(r.Context(), *body, uint(id)); err != nil {
+		if errors.Is(err, ErrInvalidSkill) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -120,6 +175,9 @@ func (h *Handler) DeleteTechnicalSkill(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteTechnicalSkill(r.Context(), uint(id)); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -152,6 +210,27 @@ func (h *Handler) GetCareers(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *Handler) GetCareer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid career ID", http.StatusBadRequest)
+		return
+	}
+
+	career, err := h.service.GetCareerByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Career not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(career)
+}
+
 func (h *Handler) CreateCareer(w http.ResponseWriter, r *http.Request) {
 	var body CareerItemDto
 
@@ -161,6 +240,10 @@ func (h *Handler) CreateCareer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.CreateCareer(r.Context(), body); err != nil {
+		if errors.Is(err, ErrInvalidCareer) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -170,6 +253,7 @@ func (h *Handler) CreateCareer(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Successfully created a career"})
 }
 
+// UpdateCareer applies a JSON merge patch to a career entry
 func (h *Handler) UpdateCareer(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
@@ -177,14 +261,29 @@ func (h *Handler) UpdateCareer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var body CareerItemDto
+	body, err := h.service.GetCareerByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Career not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	if err := utils.DecodeBody(r, &body); err != nil {
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.UpdateCareer(r.Context(), body, uint(id)); err != nil {
+	if err := h.service.UpdateCareer(r.Context(), *body, uint(id)); err != nil {
+		if errors.Is(err, ErrInvalidCareer) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -204,6 +303,9 @@ func (h *Handler) DeleteCareer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteCareer(r.Context(), uint(id)); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
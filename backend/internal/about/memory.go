@@ -0,0 +1,184 @@
+package about
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// aboutCardCount is the number of cards stored on the about page
+const aboutCardCount = 4
+
+// MemoryAboutRepository is an in-memory implementation of AboutRepository for
+// tests and database-free runs. It is safe for concurrent use.
+type MemoryAboutRepository struct {
+	mu           sync.RWMutex
+	page         *AboutPageDto
+	skills       map[uint]SkillItemDto
+	careers      map[uint]CareerItemDto
+	nextSkillID  uint
+	nextCareerID uint
+}
+
+// NewMemoryAboutRepository creates an empty MemoryAboutRepository
+func NewMemoryAboutRepository() *MemoryAboutRepository {
+	return &MemoryAboutRepository{
+		skills:       map[uint]SkillItemDto{},
+		careers:      map[uint]CareerItemDto{},
+		nextSkillID:  1,
+		nextCareerID: 1,
+	}
+}
+
+func (r *MemoryAboutRepository) Find(ctx context.Context) (*AboutPageDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.page == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	page := *r.page
+	page.Cards = slices.Clone(r.page.Cards)
+	return &page, nil
+}
+
+// Update keeps the cards data does not include, like the fixed card columns
+// of the GORM implementation
+func (r *MemoryAboutRepository) Update(ctx context.Context, data *AboutPageDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.page == nil {
+		r.page = &AboutPageDto{Cards: make([]CardDto, aboutCardCount)}
+	}
+	r.page.Description = data.Description
+	r.page.GithubLink = data.GithubLink
+	r.page.LinkedinLink = data.LinkedinLink
+	r.page.Available = data.Available
+	for i := 0; i < len(data.Cards) && i < aboutCardCount; i++ {
+		r.page.Cards[i] = data.Cards[i]
+	}
+	return nil
+}
+
+func (r *MemoryAboutRepository) GetTechnicalSkills(ctx context.Context) (*TechnicalSkillDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var skills []SkillItemDto
+	for _, id := range slices.Sorted(maps.Keys(r.skills)) {
+		skill := r.skills[id]
+		skill.Specialities = slices.Clone(skill.Specialities)
+		skills = append(skills, skill)
+	}
+	return &TechnicalSkillDto{Skills: skills}, nil
+}
+
+func (r *MemoryAboutRepository) GetTechnicalSkillByID(ctx context.Context, id uint) (*SkillItemDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	skill, ok := r.skills[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	skill.Specialities = slices.Clone(skill.Specialities)
+	return &skill, nil
+}
+
+func (r *MemoryAboutRepository) CreateTechnicalSkill(ctx context.Context, data *SkillItemDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	skill := *data
+	skill.ID = r.nextSkillID
+	skill.Specialities = slices.Clone(data.Specialities)
+	r.skills[skill.ID] = skill
+	r.nextSkillID++
+	data.ID = skill.ID
+	return nil
+}
+
+// UpdateTechnicalSkill overwrites every field of the skill, including empty
+// ones. Unknown ids are ignored.
+func (r *MemoryAboutRepository) UpdateTechnicalSkill(ctx context.Context, data *SkillItemDto, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.skills[id]; !ok {
+		return nil
+	}
+	skill := *data
+	skill.ID = id
+	skill.Specialities = slices.Clone(data.Specialities)
+	r.skills[id] = skill
+	return nil
+}
+
+func (r *MemoryAboutRepository) DeleteTechnicalSkill(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.skills, id)
+	return nil
+}
+
+func (r *MemoryAboutRepository) GetCareers(ctx context.Context) (*CareerJourneyDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var careers []CareerItemDto
+	for _, id := range slices.Sorted(maps.Keys(r.careers)) {
+		careers = append(careers, r.careers[id])
+	}
+	return &CareerJourneyDto{Careers: careers}, nil
+}
+
+func (r *MemoryAboutRepository) GetCareerByID(ctx context.Context, id uint) (*CareerItemDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	career, ok := r.careers[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &career, nil
+}
+
+func (r *MemoryAboutRepository) CreateCareer(ctx context.Context, data *CareerItemDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	career := *data
+	career.ID = r.nextCareerID
+	r.careers[career.ID] = career
+	r.nextCareerID++
+	data.ID = career.ID
+	return nil
+}
+
+// UpdateCareer overwrites every field of the career entry, including empty
+// ones. Unknown ids are ignored.
+func (r *MemoryAboutRepository) UpdateCareer(ctx context.Context, data *CareerItemDto, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.careers[id]; !ok {
+		return nil
+	}
+	career := *data
+	career.ID = id
+	r.careers[id] = career
+	return nil
+}
+
+func (r *MemoryAboutRepository) DeleteCareer(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.careers, id)
+	return nil
+}
@@ -1,5 +1,11 @@
 package about
 
+import (
+	"fmt"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+)
+
 type CardDto struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
@@ -23,6 +29,20 @@ type SkillItemDto struct {
 	YearOfExperience int      `json:"year_of_experience"`
 }
 
+// Validate checks what the database would reject, so a create or a patch
+// that sets an empty name or unknown enum answers 400 rather than 500
+func (s *SkillItemDto) Validate() error {
+	switch {
+	case s.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidSkill)
+	case !models.SkillLevel(s.Level).Valid():
+		return fmt.Errorf("%w: unknown level %q", ErrInvalidSkill, s.Level)
+	case !models.Cateogry(s.Category).Valid():
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidSkill, s.Category)
+	}
+	return nil
+}
+
 type TechnicalSkillDto struct {
 	Skills []SkillItemDto `json:"skills"`
 }
@@ -38,6 +58,17 @@ type CareerItemDto struct {
 	EndedAt     string `json:"ended_at"`
 }
 
+// Validate checks what the database would reject, like Validate on a skill
+func (c *CareerItemDto) Validate() error {
+	switch {
+	case c.Title == "":
+		return fmt.Errorf("%w: title is required", ErrInvalidCareer)
+	case !models.CareerType(c.Type).Valid():
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidCareer, c.Type)
+	}
+	return nil
+}
+
 type CareerJourneyDto struct {
 	Careers []CareerItemDto `json:"career"`
 }
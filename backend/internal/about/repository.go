@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
 	"gorm.io/gorm"
 )
 
@@ -12,10 +14,14 @@ type AboutRepository interface {
 	Find(ctx context.Context) (*AboutPageDto, error)
 	Update(ctx context.Context, data *AboutPageDto) error
 	GetTechnicalSkills(ctx context.Context) (*TechnicalSkillDto, error)
+	GetTechnicalSkillByID(ctx context.Context, id uint) (*SkillItemDto, error)
+	// CreateTechnicalSkill stores a new skill and sets data.ID to its id
 	CreateTechnicalSkill(ctx context.Context, data *SkillItemDto) error
 	UpdateTechnicalSkill(ctx context.Context, data *SkillItemDto, id uint) error
 	DeleteTechnicalSkill(ctx context.Context, id uint) error
 	GetCareers(ctx context.Context) (*CareerJourneyDto, error)
+	GetCareerByID(ctx context.Context, id uint) (*CareerItemDto, error)
+	// CreateCareer stores a new career entry and sets data.ID to its id
 	CreateCareer(ctx context.Context, data *CareerItemDto) error
 	UpdateCareer(ctx context.Context, data *CareerItemDto, id uint) error
 	DeleteCareer(ctx context.Context, id uint) error
@@ -70,67 +76,72 @@ func (r *GormAboutRepository) Find(ctx context.Context) (*AboutPageDto, error) {
 }
 
 func (r *GormAboutRepository) Update(ctx context.Context, data *AboutPageDto) error {
-	var existing models.AboutPage
-
-	err := r.db.WithContext(ctx).First(&existing).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// Create new record with embedded cards
-			aboutPage := models.AboutPage{
-				Description:  data.Description,
-				GithubLink:   data.GithubLink,
-				LinkedinLink: data.LinkedinLink,
-				Available:    data.Available,
-			}
-
-			// Map cards from DTO (up to 4)
-			if len(data.Cards) > 0 {
-				aboutPage.Card1Title = data.Cards[0].Title
-				aboutPage.Card1Desc = data.Cards[0].Description
-			}
-			if len(data.Cards) > 1 {
-				aboutPage.Card2Title = data.Cards[1].Title
-				aboutPage.Card2Desc = data.Cards[1].Description
-			}
-			if len(data.Cards) > 2 {
-				aboutPage.Card3Title = data.Cards[2].Title
-				aboutPage.Card3Desc = data.Cards[2].Description
-			}
-			if len(data.Cards) > 3 {
-				aboutPage.Card4Title = data.Cards[3].Title
-				aboutPage.Card4Desc = data.Cards[3].Description
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockPage(ctx, tx, &models.AboutPage{}); err != nil {
+			return err
+		}
+		var existing models.AboutPage
+
+		err := tx.First(&existing).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Create new record with embedded cards
+				aboutPage := models.AboutPage{
+					Description:  data.Description,
+					GithubLink:   data.GithubLink,
+					LinkedinLink: data.LinkedinLink,
+					Available:    data.Available,
+				}
+
+				// Map cards from DTO (up to 4)
+				if len(data.Cards) > 0 {
+					aboutPage.Card1Title = data.Cards[0].Title
+					aboutPage.Card1Desc = data.Cards[0].Description
+				}
+				if len(data.Cards) > 1 {
+					aboutPage.Card2Title = data.Cards[1].Title
+					aboutPage.Card2Desc = data.Cards[1].Description
+				}
+				if len(data.Cards) > 2 {
+					aboutPage.Card3Title = data.Cards[2].Title
+					aboutPage.Card3Desc = data.Cards[2].Description
+				}
+				if len(data.Cards) > 3 {
+					aboutPage.Card4Title = data.Cards[3].Title
+					aboutPage.Card4Desc = data.Cards[3].Description
+				}
+
+				return revision.RecordWrite(tx.Create(&aboutPage), revision.KindAbout, models.RevisionCreate, &aboutPage)
 			}
-
-			return r.db.WithContext(ctx).Create(&aboutPage).Error
+			return err
 		}
-		return err
-	}
 
-	// Update existing record
-	existing.Description = data.Description
-	existing.GithubLink = data.GithubLink
-	existing.LinkedinLink = data.LinkedinLink
-	existing.Available = data.Available
+		// Update existing record
+		existing.Description = data.Description
+		existing.GithubLink = data.GithubLink
+		existing.LinkedinLink = data.LinkedinLink
+		existing.Available = data.Available
 
-	// Update embedded cards
-	if len(data.Cards) > 0 {
-		existing.Card1Title = data.Cards[0].Title
-		existing.Card1Desc = data.Cards[0].Description
-	}
-	if len(data.Cards) > 1 {
-		existing.Card2Title = data.Cards[1].Title
-		existing.Card2Desc = data.Cards[1].Description
-	}
-	if len(data.Cards) > 2 {
-		existing.Card3Title = data.Cards[2].Title
-		existing.Card3Desc = data.Cards[2].Description
-	}
-	if len(data.Cards) > 3 {
-		existing.Card4Title = data.Cards[3].Title
-		existing.Card4Desc = data.Cards[3].Description
-	}
+		// Update embedded cards
+		if len(data.Cards) > 0 {
+			existing.Card1Title = data.Cards[0].Title
+			existing.Card1Desc = data.Cards[0].Description
+		}
+		if len(data.Cards) > 1 {
+			existing.Card2Title = data.Cards[1].Title
+			existing.Card2Desc = data.Cards[1].Description
+		}
+		if len(data.Cards) > 2 {
+			existing.Card3Title = data.Cards[2].Title
+			existing.Card3Desc = data.Cards[2].Description
+		}
+		if len(data.Cards) > 3 {
+			existing.Card4Title = data.Cards[3].Title
+			existing.Card4Desc = data.Cards[3].Description
+		}
 
-	return r.db.WithContext(ctx).Save(&existing).Error
+		return revision.RecordWrite(tx.Save(&existing), revision.KindAbout, models.RevisionUpdate, &existing)
+	})
 }
 
 func (r *GormAboutRepository) GetTechnicalSkills(ctx context.Context) (*TechnicalSkillDto, error) {
@@ -159,33 +170,69 @@ func (r *GormAboutRepository) GetTechnicalSkills(ctx context.Context) (*Technica
 	}, nil
 }
 
-func (r *GormAboutRepository) CreateTechnicalSkill(ctx context.Context, data *SkillItemDto) error {
-	skill := models.TechnicalSkills{
-		Name:             data.Name,
-		Description:      data.Description,
-		Specialities:     data.Specialities,
-		Level:            models.SkillLevel(data.Level),
-		Category:         models.Cateogry(data.Category),
-		YearOfExperience: data.YearOfExperience,
+func (r *GormAboutRepository) GetTechnicalSkillByID(ctx context.Context, id uint) (*SkillItemDto, error) {
+	var skill models.TechnicalSkills
+	if err := r.db.WithContext(ctx).First(&skill, id).Error; err != nil {
+		return nil, err
 	}
-
-	return r.db.WithContext(ctx).Create(&skill).Error
+	return &SkillItemDto{
+		ID:               skill.ID,
+		Name:             skill.Name,
+		Description:      skill.Description,
+		Specialities:     skill.Specialities,
+		Level:            string(skill.Level),
+		Category:         string(skill.Category),
+		YearOfExperience: skill.YearOfExperience,
+	}, nil
 }
 
-func (r *GormAboutRepository) UpdateTechnicalSkill(ctx context.Context, data *SkillItemDto, id uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Updates(
-		models.TechnicalSkills{
+func (r *GormAboutRepository) CreateTechnicalSkill(ctx context.Context, data *SkillItemDto) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		skill := models.TechnicalSkills{
 			Name:             data.Name,
 			Description:      data.Description,
 			Specialities:     data.Specialities,
 			Level:            models.SkillLevel(data.Level),
 			Category:         models.Cateogry(data.Category),
 			YearOfExperience: data.YearOfExperience,
-		}).Error
+		}
+
+		if err := revision.RecordWrite(tx.Create(&skill), revision.KindSkills, models.RevisionCreate, &skill); err != nil {
+			return err
+		}
+		data.ID = skill.ID
+		return nil
+	})
+}
+
+// UpdateTechnicalSkill overwrites every field of the skill, including empty
+// ones
+func (r *GormAboutRepository) UpdateTechnicalSkill(ctx context.Context, data *SkillItemDto, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.TechnicalSkills{}, id); err != nil {
+			return err
+		}
+		write := tx.Model(&models.TechnicalSkills{}).Where("id = ?", id).
+			Select("*").Omit("id", "created_at", "deleted_at")
+		return revision.RecordWrite(write.Updates(
+			models.TechnicalSkills{
+				Name:             data.Name,
+				Description:      data.Description,
+				Specialities:     data.Specialities,
+				Level:            models.SkillLevel(data.Level),
+				Category:         models.Cateogry(data.Category),
+				YearOfExperience: data.YearOfExperience,
+			}), revision.KindSkills, models.RevisionUpdate, &models.TechnicalSkills{ID: id})
+	})
 }
 
 func (r *GormAboutRepository) DeleteTechnicalSkill(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Unscoped().Delete(&models.TechnicalSkills{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.TechnicalSkills{}, id); err != nil {
+			return err
+		}
+		return revision.RecordWrite(tx.Where("id = ?", id).Delete(&models.TechnicalSkills{}), revision.KindSkills, models.RevisionDelete, &models.TechnicalSkills{ID: id})
+	})
 }
 
 func (r *GormAboutRepository) GetCareers(ctx context.Context) (*CareerJourneyDto, error) {
@@ -214,30 +261,68 @@ func (r *GormAboutRepository) GetCareers(ctx context.Context) (*CareerJourneyDto
 	}, nil
 }
 
+func (r *GormAboutRepository) GetCareerByID(ctx context.Context, id uint) (*CareerItemDto, error) {
+	var career models.CareerJourney
+	if err := r.db.WithContext(ctx).First(&career, id).Error; err != nil {
+		return nil, err
+	}
+	return &CareerItemDto{
+		ID:          career.ID,
+		Title:       career.Title,
+		Description: career.Description,
+		Affiliation: career.Affiliation,
+		Location:    career.Location,
+		Type:        string(career.Type),
+		StartedAt:   career.StartedAt,
+		EndedAt:     career.EndedAt,
+	}, nil
+}
+
 func (r *GormAboutRepository) CreateCareer(ctx context.Context, data *CareerItemDto) error {
-	return r.db.WithContext(ctx).Create(&models.CareerJourney{
-		Title:       data.Title,
-		Description: data.Description,
-		Affiliation: data.Affiliation,
-		Location:    data.Location,
-		Type:        models.CareerType(data.Type),
-		StartedAt:   data.StartedAt,
-		EndedAt:     data.EndedAt,
-	}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		career := models.CareerJourney{
+			Title:       data.Title,
+			Description: data.Description,
+			Affiliation: data.Affiliation,
+			Location:    data.Location,
+			Type:        models.CareerType(data.Type),
+			StartedAt:   data.StartedAt,
+			EndedAt:     data.EndedAt,
+		}
+		if err := revision.RecordWrite(tx.Create(&career), revision.KindCareers, models.RevisionCreate, &career); err != nil {
+			return err
+		}
+		data.ID = career.ID
+		return nil
+	})
 }
 
+// UpdateCareer overwrites every field of the career entry, including empty
+// ones
 func (r *GormAboutRepository) UpdateCareer(ctx context.Context, data *CareerItemDto, id uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Updates(&models.CareerJourney{
-		Title:       data.Title,
-		Description: data.Description,
-		Affiliation: data.Affiliation,
-		Location:    data.Location,
-		Type:        models.CareerType(data.Type),
-		StartedAt:   data.StartedAt,
-		EndedAt:     data.EndedAt,
-	}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.CareerJourney{}, id); err != nil {
+			return err
+		}
+		write := tx.Model(&models.CareerJourney{}).Where("id = ?", id).
+			Select("*").Omit("id", "created_at", "deleted_at")
+		return revision.RecordWrite(write.Updates(&models.CareerJourney{
+			Title:       data.Title,
+			Description: data.Description,
+			Affiliation: data.Affiliation,
+			Location:    data.Location,
+			Type:        models.CareerType(data.Type),
+			StartedAt:   data.StartedAt,
+			EndedAt:     data.EndedAt,
+		}), revision.KindCareers, models.RevisionUpdate, &models.CareerJourney{ID: id})
+	})
 }
 
 func (r *GormAboutRepository) DeleteCareer(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Unscoped().Delete(&models.CareerJourney{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.CareerJourney{}, id); err != nil {
+			return err
+		}
+		return revision.RecordWrite(tx.Where("id = ?", id).Delete(&models.CareerJourney{}), revision.KindCareers, models.RevisionDelete, &models.CareerJourney{ID: id})
+	})
 }
@@ -0,0 +1,20 @@
+package about_test
+
+import (
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+)
+
+func TestMemoryAboutRepository(t *testing.T) {
+	repotest.RunAboutRepository(t, func(t *testing.T) about.AboutRepository {
+		return about.NewMemoryAboutRepository()
+	})
+}
+
+func TestGormAboutRepository(t *testing.T) {
+	repotest.RunAboutRepository(t, func(t *testing.T) about.AboutRepository {
+		return about.NewGormAboutRepository(repotest.NewSQLiteDB(t))
+	})
+}
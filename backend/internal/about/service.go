@@ -2,14 +2,25 @@ package about
 
 import (
 	"context"
+	"errors"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+)
+
+var (
+	ErrInvalidSkill  = errors.New("invalid skill")
+	ErrInvalidCareer = errors.New("invalid career")
 )
 
 type Service struct {
-	repo AboutRepository
+	repo       AboutRepository
+	invalidate cache.Invalidator
 }
 
-func NewService(repo AboutRepository) *Service {
-	return &Service{repo}
+// NewService calls invalidate after the about page, a skill or a career
+// entry changed
+func NewService(repo AboutRepository, invalidate cache.Invalidator) *Service {
+	return &Service{repo, invalidate}
 }
 
 func (s *Service) Find(ctx context.Context) (*AboutPageDto, error) {
@@ -17,37 +28,85 @@ func (s *Service) Find(ctx context.Context) (*AboutPageDto, error) {
 }
 
 func (s *Service) Update(ctx context.Context, data AboutPageDto) error {
-	return s.repo.Update(ctx, &data)
+	if err := s.repo.Update(ctx, &data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagAbout)
+	return nil
 }
 
 func (s *Service) GetTechnicalSkills(ctx context.Context) (*TechnicalSkillDto, error) {
 	return s.repo.GetTechnicalSkills(ctx)
 }
 
+func (s *Service) GetTechnicalSkillByID(ctx context.Context, id uint) (*SkillItemDto, error) {
+	return s.repo.GetTechnicalSkillByID(ctx, id)
+}
+
 func (s *Service) CreateTechnicalSkill(ctx context.Context, data SkillItemDto) error {
-	return s.repo.CreateTechnicalSkill(ctx, &data)
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.CreateTechnicalSkill(ctx, &data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagSkills)
+	return nil
 }
 
 func (s *Service) UpdateTechnicalSkill(ctx context.Context, data SkillItemDto, id uint) error {
-	return s.repo.UpdateTechnicalSkill(ctx, &data, id)
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateTechnicalSkill(ctx, &data, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagSkills)
+	return nil
 }
 
 func (s *Service) DeleteTechnicalSkill(ctx context.Context, id uint) error {
-	return s.repo.DeleteTechnicalSkill(ctx, id)
+	if err := s.repo.DeleteTechnicalSkill(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagSkills)
+	return nil
 }
 
 func (s *Service) GetCareers(ctx context.Context) (*CareerJourneyDto, error) {
 	return s.repo.GetCareers(ctx)
 }
 
+func (s *Service) GetCareerByID(ctx context.Context, id uint) (*CareerItemDto, error) {
+	return s.repo.GetCareerByID(ctx, id)
+}
+
 func (s *Service) CreateCareer(ctx context.Context, data CareerItemDto) error {
-	return s.repo.CreateCareer(ctx, &data)
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.CreateCareer(ctx, &data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagCareers)
+	return nil
 }
 
 func (s *Service) UpdateCareer(ctx context.Context, data CareerItemDto, id uint) error {
-	return s.repo.UpdateCareer(ctx, &data, id)
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateCareer(ctx, &data, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagCareers)
+	return nil
 }
 
 func (s *Service) DeleteCareer(ctx context.Context, id uint) error {
-	return s.repo.DeleteCareer(ctx, id)
+	if err := s.repo.DeleteCareer(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagCareers)
+	return nil
 }
@@ -0,0 +1,92 @@
+package about_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+)
+
+func TestAboutWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	inv := &repotest.Invalidations{}
+	s := about.NewService(about.NewMemoryAboutRepository(), inv.Invalidate)
+
+	if err := s.Update(ctx, about.AboutPageDto{Description: "Hello"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	skill := about.SkillItemDto{Name: "Go", Level: string(models.Expert), Category: string(models.Backend)}
+	if err := s.CreateTechnicalSkill(ctx, skill); err != nil {
+		t.Fatalf("CreateTechnicalSkill: %v", err)
+	}
+	skill.Name = "Golang"
+	if err := s.UpdateTechnicalSkill(ctx, skill, 1); err != nil {
+		t.Fatalf("UpdateTechnicalSkill: %v", err)
+	}
+	if err := s.DeleteTechnicalSkill(ctx, 1); err != nil {
+		t.Fatalf("DeleteTechnicalSkill: %v", err)
+	}
+	career := about.CareerItemDto{Title: "Engineer", Type: string(models.Job)}
+	if err := s.CreateCareer(ctx, career); err != nil {
+		t.Fatalf("CreateCareer: %v", err)
+	}
+	career.Title = "Senior engineer"
+	if err := s.UpdateCareer(ctx, career, 1); err != nil {
+		t.Fatalf("UpdateCareer: %v", err)
+	}
+	if err := s.DeleteCareer(ctx, 1); err != nil {
+		t.Fatalf("DeleteCareer: %v", err)
+	}
+
+	want := repotest.Invalidations{
+		{cache.TagAbout},
+		{cache.TagSkills}, {cache.TagSkills}, {cache.TagSkills},
+		{cache.TagCareers}, {cache.TagCareers}, {cache.TagCareers},
+	}
+	if !slices.EqualFunc(*inv, want, slices.Equal) {
+		t.Errorf("invalidated %v, want %v", *inv, want)
+	}
+}
+
+func TestAboutRejectsInvalidItems(t *testing.T) {
+	ctx := context.Background()
+	inv := &repotest.Invalidations{}
+	s := about.NewService(about.NewMemoryAboutRepository(), inv.Invalidate)
+
+	skill := about.SkillItemDto{Name: "Go", Level: string(models.Expert), Category: string(models.Backend)}
+	if err := s.CreateTechnicalSkill(ctx, skill); err != nil {
+		t.Fatalf("CreateTechnicalSkill: %v", err)
+	}
+	for _, patched := range []about.SkillItemDto{
+		{Level: string(models.Expert), Category: string(models.Backend)},
+		{Name: "Go", Category: string(models.Backend)},
+		{Name: "Go", Level: "Guru", Category: string(models.Backend)},
+		{Name: "Go", Level: string(models.Expert)},
+	} {
+		if err := s.UpdateTechnicalSkill(ctx, patched, 1); !errors.Is(err, about.ErrInvalidSkill) {
+			t.Errorf("UpdateTechnicalSkill(%+v) = %v, want ErrInvalidSkill", patched, err)
+		}
+	}
+	if err := s.CreateCareer(ctx, about.CareerItemDto{Title: "Engineer", Type: "Hobby"}); !errors.Is(err, about.ErrInvalidCareer) {
+		t.Errorf("CreateCareer with unknown type = %v, want ErrInvalidCareer", err)
+	}
+	if err := s.UpdateCareer(ctx, about.CareerItemDto{Type: string(models.Job)}, 1); !errors.Is(err, about.ErrInvalidCareer) {
+		t.Errorf("UpdateCareer without title = %v, want ErrInvalidCareer", err)
+	}
+
+	got, err := s.GetTechnicalSkillByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTechnicalSkillByID: %v", err)
+	}
+	if got.Name != skill.Name || got.Level != skill.Level || got.Category != skill.Category {
+		t.Errorf("skill = %+v after rejected updates, want %+v", *got, skill)
+	}
+	if want := (repotest.Invalidations{{cache.TagSkills}}); !slices.EqualFunc(*inv, want, slices.Equal) {
+		t.Errorf("invalidated %v, want %v", *inv, want)
+	}
+}
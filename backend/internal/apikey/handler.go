@@ -0,0 +1,106 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	keys, err := h.service.GetAPIKeys(r.Context(), actor)
+	if err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"length": len(keys.APIKeys),
+		"data":   keys.APIKeys,
+	})
+}
+
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	var body CreateAPIKeyDto
+	if err := utils.DecodeBody(r, &body); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	key, err := h.service.CreateAPIKey(r.Context(), actor, &body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, key)
+}
+
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+	if err := h.service.RevokeAPIKey(r.Context(), actor, uint(id)); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// currentActor resolves the signed-in user. API keys cannot manage API keys,
+// otherwise a leaked key could mint longer-lived replacements for itself.
+func currentActor(w http.ResponseWriter, r *http.Request) (Actor, bool) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return Actor{}, false
+	}
+	if claims.APIKeyID != 0 {
+		utils.WriteError(w, http.StatusForbidden, "API keys cannot manage API keys")
+		return Actor{}, false
+	}
+	id, err := strconv.ParseUint(claims.Sub, 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return Actor{}, false
+	}
+	return Actor{UserID: uint(id), Role: models.UserRole(claims.Role)}, true
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrAPIKeyNotFound):
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrScopeNotGranted):
+		utils.WriteError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidScope),
+		errors.Is(err, ErrNoScopes), errors.Is(err, ErrInvalidExpiry):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.WriteServerError(w, err)
+	}
+}
@@ -0,0 +1,30 @@
+package apikey
+
+type APIKeyItemDto struct {
+	ID         uint     `json:"id"`
+	UserID     uint     `json:"user_id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+	RevokedAt  *string  `json:"revoked_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type APIKeyDto struct {
+	APIKeys []APIKeyItemDto `json:"api_keys"`
+}
+
+type CreateAPIKeyDto struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays of 0 creates a key that never expires
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+// CreatedAPIKeyDto is the only response that ever contains the full key
+type CreatedAPIKeyDto struct {
+	APIKeyItemDto
+	Key string `json:"key"`
+}
@@ -0,0 +1,73 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	GetAPIKeys(ctx context.Context, userID *uint) ([]models.APIKey, error)
+	GetAPIKeyByID(ctx context.Context, id uint) (*models.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	RevokeAPIKey(ctx context.Context, id uint) error
+	TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error
+}
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+// GetAPIKeys lists keys, optionally restricted to a single user
+func (r *GormAPIKeyRepository) GetAPIKeys(ctx context.Context, userID *uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	query := r.db.WithContext(ctx).Order("id")
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if err := query.Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *GormAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *GormAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *GormAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *GormAPIKeyRepository) RevokeAPIKey(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *GormAPIKeyRepository) TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+}
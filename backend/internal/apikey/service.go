@@ -0,0 +1,212 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/user"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Keys look like "pfk_<12 hex chars>_<secret>"; everything before the secret
+// is stored in clear so a key can be identified without revealing it.
+const idLength = 12
+
+// lastUsedResolution limits how often last_used_at is written per key
+const lastUsedResolution = time.Minute
+
+var (
+	ErrInvalidAPIKey   = errors.New("invalid API key")
+	ErrAPIKeyNotFound  = errors.New("API key not found")
+	ErrInvalidName     = errors.New("API key name is required")
+	ErrInvalidScope    = errors.New("unknown scope")
+	ErrNoScopes        = errors.New("at least one scope is required")
+	ErrScopeNotGranted = errors.New("cannot grant a scope your role does not have")
+	ErrInvalidExpiry   = errors.New("expires_in_days must not be negative")
+	ErrForbidden       = errors.New("not allowed to manage this API key")
+)
+
+type Service struct {
+	repo  APIKeyRepository
+	users *user.Service
+}
+
+func NewService(repo APIKeyRepository, users *user.Service) *Service {
+	return &Service{repo, users}
+}
+
+// Actor identifies the signed-in user managing API keys
+type Actor struct {
+	UserID uint
+	Role   models.UserRole
+}
+
+// GetAPIKeys lists the actor's keys; owners see every key
+func (s *Service) GetAPIKeys(ctx context.Context, actor Actor) (*APIKeyDto, error) {
+	var filter *uint
+	if actor.Role != models.RoleOwner {
+		filter = &actor.UserID
+	}
+
+	keys, err := s.repo.GetAPIKeys(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoKeys := []APIKeyItemDto{}
+	for _, k := range keys {
+		dtoKeys = append(dtoKeys, toDto(&k))
+	}
+	return &APIKeyDto{APIKeys: dtoKeys}, nil
+}
+
+// CreateAPIKey issues a new key limited to scopes the actor's role grants
+func (s *Service) CreateAPIKey(ctx context.Context, actor Actor, data *CreateAPIKeyDto) (*CreatedAPIKeyDto, error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+	if len(data.Scopes) == 0 {
+		return nil, ErrNoScopes
+	}
+	for _, scope := range data.Scopes {
+		if !models.Scope(scope).Valid() {
+			return nil, ErrInvalidScope
+		}
+		if !actor.Role.HasScope(models.Scope(scope)) {
+			return nil, ErrScopeNotGranted
+		}
+	}
+	if data.ExpiresInDays < 0 {
+		return nil, ErrInvalidExpiry
+	}
+
+	idBytes := make([]byte, idLength/2)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+	secret, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	prefix := models.APIKeyTokenPrefix + hex.EncodeToString(idBytes)
+	raw := prefix + "_" + secret
+
+	key := &models.APIKey{
+		UserID:  actor.UserID,
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: utils.HashToken(raw),
+		Scopes:  slices.Compact(slices.Sorted(slices.Values(data.Scopes))),
+	}
+	if data.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, data.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKeyDto{APIKeyItemDto: toDto(key), Key: raw}, nil
+}
+
+// RevokeAPIKey revokes one of the actor's keys; owners may revoke any key
+func (s *Service) RevokeAPIKey(ctx context.Context, actor Actor, id uint) error {
+	key, err := s.repo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	if key.UserID != actor.UserID && actor.Role != models.RoleOwner {
+		return ErrForbidden
+	}
+	return s.repo.RevokeAPIKey(ctx, id)
+}
+
+// VerifyAPIKey authenticates a raw key and returns the principal it acts as.
+// The effective scopes are the key's scopes that the owner's current role
+// still grants, so demoting a user also narrows their keys.
+func (s *Service) VerifyAPIKey(ctx context.Context, raw string) (*utils.JWTClaims, error) {
+	rest, ok := strings.CutPrefix(raw, models.APIKeyTokenPrefix)
+	if !ok || len(rest) < idLength+2 || rest[idLength] != '_' {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.GetAPIKeyByPrefix(ctx, models.APIKeyTokenPrefix+rest[:idLength])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(utils.HashToken(raw))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	owner, err := s.users.GetUser(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	scopes := []string{}
+	for _, scope := range key.Scopes {
+		if owner.Role.HasScope(models.Scope(scope)) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastUsedResolution {
+		if err := s.repo.TouchAPIKey(ctx, key.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return &utils.JWTClaims{
+		Sub:      strconv.FormatUint(uint64(owner.ID), 10),
+		Role:     string(owner.Role),
+		APIKeyID: key.ID,
+		Scopes:   scopes,
+	}, nil
+}
+
+func toDto(k *models.APIKey) APIKeyItemDto {
+	return APIKeyItemDto{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  formatTime(k.ExpiresAt),
+		LastUsedAt: formatTime(k.LastUsedAt),
+		RevokedAt:  formatTime(k.RevokedAt),
+		CreatedAt:  k.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
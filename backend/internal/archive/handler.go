@@ -0,0 +1,79 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Export handles GET /admin/export and downloads the archive as a file
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	archive, err := h.service.Export(r.Context())
+	if err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("portfolio-export-%s.json", archive.ExportedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	logger.Info("Exported content", "by", actor(r))
+	utils.WriteJSON(w, http.StatusOK, archive)
+}
+
+// Import handles POST /admin/import?mode=merge|replace&dry_run=true
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	mode := ImportMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = ImportMerge
+	}
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid dry_run value")
+			return
+		}
+		dryRun = parsed
+	}
+
+	var body ArchiveDto
+	if err := utils.DecodeBody(r, &body); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start := time.Now()
+	result, err := h.service.Import(r.Context(), &body, mode, dryRun)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMode) || errors.Is(err, ErrUnsupportedVersion) || errors.Is(err, ErrInvalidArchive) {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	logger.Info("Imported content", "mode", mode, "dry_run", dryRun, "duration", time.Since(start), "by", actor(r))
+	utils.WriteJSON(w, http.StatusOK, result)
+}
+
+func actor(r *http.Request) string {
+	if claims := middleware.GetUserFromContext(r.Context()); claims != nil {
+		return claims.Sub
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package archive
+
+import (
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"github.com/othersidedrl/portfolio/backend/internal/hero"
+	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+)
+
+// ArchiveVersion is the format written by export; import rejects others
+const ArchiveVersion = 1
+
+// ArchiveDto is a full copy of the portfolio content. Item ids are exported
+// for reference only: import matches items by their natural keys instead, so
+// archives can move between databases. Images stay on Cloudinary and are
+// kept as URLs.
+type ArchiveDto struct {
+	Version       int                          `json:"version"`
+	ExportedAt    time.Time                    `json:"exported_at"`
+	Hero          *hero.HeroPageDto            `json:"hero,omitempty"`
+	About         *about.AboutPageDto          `json:"about,omitempty"`
+	Skills        []about.SkillItemDto         `json:"skills"`
+	Careers       []about.CareerItemDto        `json:"careers"`
+	TestimonyPage *testimony.TestimonyPageDto  `json:"testimony_page,omitempty"`
+	Testimonies   []testimony.TestimonyItemDto `json:"testimonies"`
+	ProjectPage   *project.ProjectPageDto      `json:"project_page,omitempty"`
+	Projects      []project.ProjectItemDto     `json:"projects"`
+}
+
+// ImportMode decides what happens to content missing from the archive
+type ImportMode string
+
+const (
+	// ImportMerge updates items that match an archived one and creates the
+	// rest, leaving other content alone
+	ImportMerge ImportMode = "merge"
+	// ImportReplace moves all current items to the trash first, so the
+	// archive becomes the whole portfolio
+	ImportReplace ImportMode = "replace"
+)
+
+type ImportCountsDto struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Trashed int `json:"trashed"`
+}
+
+// ImportResultDto reports what an import changed, or would change on a dry run
+type ImportResultDto struct {
+	Mode   ImportMode `json:"mode"`
+	DryRun bool       `json:"dry_run"`
+	// Pages lists the single-row pages that were overwritten
+	Pages       []string        `json:"pages"`
+	Skills      ImportCountsDto `json:"skills"`
+	Careers     ImportCountsDto `json:"careers"`
+	Testimonies ImportCountsDto `json:"testimonies"`
+	Projects    ImportCountsDto `json:"projects"`
+}
@@ -0,0 +1,276 @@
+package archive
+
+import (
+	"context"
+	"errors"
+
+	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"github.com/othersidedrl/portfolio/backend/internal/hero"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+	"gorm.io/gorm"
+)
+
+// errDryRun rolls back the import transaction after a dry run
+var errDryRun = errors.New("dry run")
+
+// ArchiveRepository reads and writes the whole portfolio at once
+type ArchiveRepository interface {
+	Export(ctx context.Context) (*ArchiveDto, error)
+	Import(ctx context.Context, archive *ArchiveDto, mode ImportMode, dryRun bool) (*ImportResultDto, error)
+}
+
+type GormArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewGormArchiveRepository(db *gorm.DB) *GormArchiveRepository {
+	return &GormArchiveRepository{db: db}
+}
+
+// Export reads every section in one transaction so the archive is consistent
+func (r *GormArchiveRepository) Export(ctx context.Context) (*ArchiveDto, error) {
+	archive := &ArchiveDto{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		heroRepo := hero.NewGormHeroRepository(tx)
+		aboutRepo := about.NewGormAboutRepository(tx)
+		testimonyRepo := testimony.NewGormTestimonyRepository(tx)
+		projectRepo := project.NewGormProjectRepository(tx)
+
+		var err error
+		if archive.Hero, err = optional(heroRepo.Find(ctx)); err != nil {
+			return err
+		}
+		if archive.About, err = optional(aboutRepo.Find(ctx)); err != nil {
+			return err
+		}
+		if archive.TestimonyPage, err = optional(testimonyRepo.GetTestimonyPage(ctx)); err != nil {
+			return err
+		}
+		if archive.ProjectPage, err = optional(projectRepo.GetProjectPage(ctx)); err != nil {
+			return err
+		}
+
+		skills, err := aboutRepo.GetTechnicalSkills(ctx)
+		if err != nil {
+			return err
+		}
+		careers, err := aboutRepo.GetCareers(ctx)
+		if err != nil {
+			return err
+		}
+		testimonies, err := testimonyRepo.GetTestimonies(ctx)
+		if err != nil {
+			return err
+		}
+		projects, err := projectRepo.GetProjects(ctx)
+		if err != nil {
+			return err
+		}
+		archive.Skills = append([]about.SkillItemDto{}, skills.Skills...)
+		archive.Careers = append([]about.CareerItemDto{}, careers.Careers...)
+		archive.Testimonies = append([]testimony.TestimonyItemDto{}, testimonies.Testimonies...)
+		archive.Projects = append([]project.ProjectItemDto{}, projects.Projects...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// Import applies the archive in a single transaction; a failure or a dry run
+// rolls everything back
+func (r *GormArchiveRepository) Import(ctx context.Context, archive *ArchiveDto, mode ImportMode, dryRun bool) (*ImportResultDto, error) {
+	result := &ImportResultDto{Mode: mode, DryRun: dryRun, Pages: []string{}}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := importPages(ctx, tx, archive, result); err != nil {
+			return err
+		}
+
+		if mode == ImportReplace {
+			trashed := []struct {
+				model  interface{}
+				kind   revision.Kind
+				counts *ImportCountsDto
+			}{
+				{&models.TechnicalSkills{}, revision.KindSkills, &result.Skills},
+				{&models.CareerJourney{}, revision.KindCareers, &result.Careers},
+				{&models.Testimony{}, "", &result.Testimonies},
+				{&models.Project{}, revision.KindProjects, &result.Projects},
+			}
+			for _, t := range trashed {
+				var ids []uint
+				if err := tx.Model(t.model).Pluck("id", &ids).Error; err != nil {
+					return err
+				}
+				if len(ids) == 0 {
+					continue
+				}
+				if err := tx.Where("id IN ?", ids).Delete(t.model).Error; err != nil {
+					return err
+				}
+				if err := recordAll(tx, t.kind, models.RevisionDelete, ids); err != nil {
+					return err
+				}
+				t.counts.Trashed = len(ids)
+			}
+		}
+
+		for _, s := range archive.Skills {
+			row := &models.TechnicalSkills{
+				Name:             s.Name,
+				Description:      s.Description,
+				Specialities:     s.Specialities,
+				Level:            models.SkillLevel(s.Level),
+				Category:         models.Cateogry(s.Category),
+				YearOfExperience: s.YearOfExperience,
+			}
+			if err := upsert(tx, mode, revision.KindSkills, row, &result.Skills, "name = ?", s.Name); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range archive.Careers {
+			row := &models.CareerJourney{
+				StartedAt:   c.StartedAt,
+				EndedAt:     c.EndedAt,
+				Title:       c.Title,
+				Affiliation: c.Affiliation,
+				Description: c.Description,
+				Location:    c.Location,
+				Type:        models.CareerType(c.Type),
+			}
+			err := upsert(tx, mode, revision.KindCareers, row, &result.Careers,
+				"title = ? AND affiliation = ? AND started_at = ?", c.Title, c.Affiliation, c.StartedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, t := range archive.Testimonies {
+			row := &models.Testimony{
+				Name:        t.Name,
+				ProfileUrl:  t.ProfileUrl,
+				Affiliation: t.Affiliation,
+				Rating:      t.Rating,
+				Description: t.Description,
+				AISummary:   t.AISummary,
+				Approved:    t.Approved,
+			}
+			err := upsert(tx, mode, "", row, &result.Testimonies,
+				"name = ? AND description = ?", t.Name, t.Description)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, p := range archive.Projects {
+			row := &models.Project{
+				Name:         p.Name,
+				ImageUrls:    p.ImageUrls,
+				Description:  p.Description,
+				TechStack:    p.TechStack,
+				GithubLink:   p.GithubLink,
+				Type:         p.Type,
+				Contribution: p.Contribution,
+				ProjectLink:  p.ProjectLink,
+			}
+			if err := upsert(tx, mode, revision.KindProjects, row, &result.Projects, "name = ?", p.Name); err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importPages overwrites the single-row pages present in the archive
+func importPages(ctx context.Context, tx *gorm.DB, archive *ArchiveDto, result *ImportResultDto) error {
+	if archive.Hero != nil {
+		if err := hero.NewGormHeroRepository(tx).Update(ctx, archive.Hero); err != nil {
+			return err
+		}
+		result.Pages = append(result.Pages, "hero")
+	}
+	if archive.About != nil {
+		if err := about.NewGormAboutRepository(tx).Update(ctx, archive.About); err != nil {
+			return err
+		}
+		result.Pages = append(result.Pages, "about")
+	}
+	if archive.TestimonyPage != nil {
+		if err := testimony.NewGormTestimonyRepository(tx).UpdateTestimonyPage(ctx, archive.TestimonyPage); err != nil {
+			return err
+		}
+		result.Pages = append(result.Pages, "testimony_page")
+	}
+	if archive.ProjectPage != nil {
+		if err := project.NewGormProjectRepository(tx).UpdateProjectPage(ctx, archive.ProjectPage); err != nil {
+			return err
+		}
+		result.Pages = append(result.Pages, "project_page")
+	}
+	return nil
+}
+
+// upsert creates row, or in merge mode first overwrites every column of the
+// live rows matching the natural key given by query. Writes are recorded as
+// revisions of kind unless kind is empty.
+func upsert[M any](tx *gorm.DB, mode ImportMode, kind revision.Kind, row *M, counts *ImportCountsDto, query string, args ...interface{}) error {
+	if mode == ImportMerge {
+		var ids []uint
+		if err := tx.Model(new(M)).Where(query, args...).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			err := tx.Model(new(M)).Where("id IN ?", ids).
+				Select("*").Omit("id", "created_at", "deleted_at").
+				Updates(row).Error
+			if err != nil {
+				return err
+			}
+			counts.Updated++
+			return recordAll(tx, kind, models.RevisionUpdate, ids)
+		}
+	}
+
+	if err := tx.Create(row).Error; err != nil {
+		return err
+	}
+	counts.Created++
+	if kind == "" {
+		return nil
+	}
+	return revision.Record(tx, kind, models.RevisionCreate, row)
+}
+
+// recordAll records a revision of kind for each id; an empty kind is not tracked
+func recordAll(tx *gorm.DB, kind revision.Kind, action models.RevisionAction, ids []uint) error {
+	if kind == "" {
+		return nil
+	}
+	for _, id := range ids {
+		if err := revision.RecordID(tx, kind, action, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optional turns a missing single-row page into nil
+func optional[T any](v *T, err error) (*T, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return v, err
+}
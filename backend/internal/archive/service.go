@@ -0,0 +1,105 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported archive version")
+	ErrInvalidMode        = errors.New("import mode must be merge or replace")
+	ErrInvalidArchive     = errors.New("invalid archive")
+)
+
+type Service struct {
+	repo       ArchiveRepository
+	invalidate cache.Invalidator
+}
+
+// NewService calls invalidate for every tag after an import, which can
+// change any section
+func NewService(repo ArchiveRepository, invalidate cache.Invalidator) *Service {
+	return &Service{repo: repo, invalidate: invalidate}
+}
+
+// Export returns the current content stamped with the archive version
+func (s *Service) Export(ctx context.Context) (*ArchiveDto, error) {
+	archive, err := s.repo.Export(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archive.Version = ArchiveVersion
+	archive.ExportedAt = time.Now().UTC()
+	return archive, nil
+}
+
+// Import validates the whole archive before touching the database, then
+// applies it. With dryRun nothing is kept but the result is still reported.
+func (s *Service) Import(ctx context.Context, archive *ArchiveDto, mode ImportMode, dryRun bool) (*ImportResultDto, error) {
+	if mode != ImportMerge && mode != ImportReplace {
+		return nil, ErrInvalidMode
+	}
+	if archive.Version != ArchiveVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, archive.Version)
+	}
+	if err := validate(archive); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.Import(ctx, archive, mode, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		s.invalidate(ctx, cache.Tags...)
+	}
+	return result, nil
+}
+
+func validate(archive *ArchiveDto) error {
+	if archive.About != nil && len(archive.About.Cards) != 4 {
+		return fmt.Errorf("%w: about must have exactly 4 cards", ErrInvalidArchive)
+	}
+
+	for i, s := range archive.Skills {
+		switch {
+		case s.Name == "":
+			return fmt.Errorf("%w: skills[%d] has no name", ErrInvalidArchive, i)
+		case !models.SkillLevel(s.Level).Valid():
+			return fmt.Errorf("%w: skills[%d] has unknown level %q", ErrInvalidArchive, i, s.Level)
+		case !models.Cateogry(s.Category).Valid():
+			return fmt.Errorf("%w: skills[%d] has unknown category %q", ErrInvalidArchive, i, s.Category)
+		}
+	}
+
+	for i, c := range archive.Careers {
+		switch {
+		case c.Title == "":
+			return fmt.Errorf("%w: careers[%d] has no title", ErrInvalidArchive, i)
+		case !models.CareerType(c.Type).Valid():
+			return fmt.Errorf("%w: careers[%d] has unknown type %q", ErrInvalidArchive, i, c.Type)
+		}
+	}
+
+	for i, t := range archive.Testimonies {
+		if t.Name == "" {
+			return fmt.Errorf("%w: testimonies[%d] has no name", ErrInvalidArchive, i)
+		}
+	}
+
+	for i, p := range archive.Projects {
+		switch {
+		case p.Name == "":
+			return fmt.Errorf("%w: projects[%d] has no name", ErrInvalidArchive, i)
+		case !p.Type.Valid():
+			return fmt.Errorf("%w: projects[%d] has unknown type %q", ErrInvalidArchive, i, p.Type)
+		case !p.Contribution.Valid():
+			return fmt.Errorf("%w: projects[%d] has unknown contribution %q", ErrInvalidArchive, i, p.Contribution)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+// refreshCookiePath limits the refresh cookie to the auth endpoints
+const refreshCookiePath = "/api/v1/auth"
+
+// CookieConfig controls the cookies set for cookie-based sessions. Without
+// Enabled, logins asking for session cookies are rejected.
+type CookieConfig struct {
+	Enabled    bool
+	Domain     string
+	Secure     bool
+	SameSite   http.SameSite
+	RefreshTTL time.Duration
+}
+
+// ParseSameSite maps "strict", "lax" or "none" to an http.SameSite mode,
+// defaulting to strict
+func ParseSameSite(mode string) http.SameSite {
+	switch strings.ToLower(mode) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
+
+// rejectSessionCookie answers 400 when a client asks for session cookies
+// while they are disabled
+func (h *Handler) rejectSessionCookie(w http.ResponseWriter, sessionCookie bool) bool {
+	if sessionCookie && !h.cookies.Enabled {
+		http.Error(w, "Session cookies are disabled", http.StatusBadRequest)
+		return true
+	}
+	return false
+}
+
+// setSessionCookies moves the tokens into HttpOnly cookies and issues a new
+// CSRF token. The tokens are removed from the response body so scripts on the
+// page never see them.
+func (h *Handler) setSessionCookies(w http.ResponseWriter, tokens *TokenResponse) error {
+	csrf, err := utils.RandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	refreshMaxAge := int(h.cookies.RefreshTTL.Seconds())
+	http.SetCookie(w, h.cookie(middleware.AccessTokenCookie, tokens.Token, "/", tokens.ExpiresIn, true))
+	http.SetCookie(w, h.cookie(middleware.RefreshTokenCookie, tokens.RefreshToken, refreshCookiePath, refreshMaxAge, true))
+	// Readable by the CMS so it can echo it in the X-CSRF-Token header
+	http.SetCookie(w, h.cookie(middleware.CSRFCookie, csrf, "/", refreshMaxAge, false))
+
+	tokens.Token = ""
+	tokens.RefreshToken = ""
+	tokens.CSRFToken = csrf
+	return nil
+}
+
+// clearSessionCookies expires every session cookie
+func (h *Handler) clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, h.cookie(middleware.AccessTokenCookie, "", "/", -1, true))
+	http.SetCookie(w, h.cookie(middleware.RefreshTokenCookie, "", refreshCookiePath, -1, true))
+	http.SetCookie(w, h.cookie(middleware.CSRFCookie, "", "/", -1, false))
+}
+
+func (h *Handler) cookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   h.cookies.Domain,
+		MaxAge:   maxAge,
+		Secure:   h.cookies.Secure,
+		HttpOnly: httpOnly,
+		SameSite: h.cookies.SameSite,
+	}
+}
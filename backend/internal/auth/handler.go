@@ -2,9 +2,15 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/othersidedrl/portfolio/backend/internal/lockout"
 	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"github.com/othersidedrl/portfolio/backend/internal/user"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
 )
 
@@ -12,12 +18,13 @@ import (
 // It's equivalent to a NestJS controller class with a service dependency.
 type Handler struct {
 	service *Service
+	cookies CookieConfig
 }
 
 // NewHandler returns a new instance of the auth handler.
 // It's like injecting AuthService in NestJS.
-func NewHandler(service *Service) *Handler {
-	return &Handler{service}
+func NewHandler(service *Service, cookies CookieConfig) *Handler {
+	return &Handler{service, cookies}
 }
 
 // Login handles POST /auth/login.
@@ -30,19 +37,168 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if h.rejectSessionCookie(w, req.SessionCookie) {
+		return
+	}
 
 	// Call the service layer
-	token, err := h.service.Login(req.Email, req.Password)
+	result, err := h.service.Login(r.Context(), req.Email, req.Password, clientInfo(r))
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		if writeThrottleError(w, err) {
+			return
+		}
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	if req.SessionCookie && result.TokenResponse != nil {
+		if err := h.setSessionCookies(w, result.TokenResponse); err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+	}
+
+	// Return the tokens (or the two-factor challenge) in JSON format
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ExchangeTicket handles POST /auth/oidc/exchange.
+// It finishes an external login with the ticket from the callback redirect.
+func (h *Handler) ExchangeTicket(w http.ResponseWriter, r *http.Request) {
+	var req ExchangeTicketRequest
+
+	if err := utils.DecodeBody(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.rejectSessionCookie(w, req.SessionCookie) {
+		return
+	}
+
+	result, err := h.service.ExchangeLoginTicket(r.Context(), req.Ticket, clientInfo(r))
+	if err != nil {
+		if errors.Is(err, token.ErrInvalidLoginTicket) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	if req.SessionCookie && result.TokenResponse != nil {
+		if err := h.setSessionCookies(w, result.TokenResponse); err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// VerifyLogin handles POST /auth/login/verify.
+// It completes a two-factor login with a TOTP or recovery code.
+func (h *Handler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	var req VerifyLoginRequest
+
+	if err := utils.DecodeBody(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.rejectSessionCookie(w, req.SessionCookie) {
+		return
+	}
+
+	tokens, err := h.service.VerifyLogin(r.Context(), &req, clientInfo(r))
+	if err != nil {
+		if writeThrottleError(w, err) {
+			return
+		}
+		if errors.Is(err, token.ErrInvalidChallenge) || errors.Is(err, user.ErrInvalidTOTPCode) ||
+			errors.Is(err, user.ErrTOTPNotEnabled) || errors.Is(err, user.ErrUserNotFound) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	if req.SessionCookie {
+		if err := h.setSessionCookies(w, tokens); err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// Refresh handles POST /auth/refresh.
+// It exchanges a refresh token for a new access/refresh token pair.
+// Without a refresh token in the body, the refresh cookie is used and the
+// new pair is set as cookies again.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+
+	if err := utils.DecodeBody(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Return the token in JSON format
+	fromCookie := false
+	if req.RefreshToken == "" && h.cookies.Enabled {
+		if cookie, err := r.Cookie(middleware.RefreshTokenCookie); err == nil {
+			req.RefreshToken = cookie.Value
+			fromCookie = true
+		}
+	}
+
+	tokens, err := h.service.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, token.ErrInvalidRefreshToken) || errors.Is(err, token.ErrRefreshTokenReused) {
+			if fromCookie {
+				h.clearSessionCookies(w)
+			}
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	if fromCookie {
+		if err := h.setSessionCookies(w, tokens); err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"token": token,
-	})
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// Logout handles POST /auth/logout.
+// It revokes the current access token and every refresh token of its session.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), claims); err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	h.clearSessionCookies(w)
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
@@ -53,9 +209,41 @@ func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := map[string]string{
-		"id": claims.Sub,
+		"id":   claims.Sub,
+		"role": claims.Role,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// JWKS handles GET /.well-known/jwks.json.
+// Other services use it to verify access tokens without the signing secret.
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	utils.WriteJSON(w, http.StatusOK, h.service.JWKS())
+}
+
+func clientInfo(r *http.Request) ClientInfo {
+	return ClientInfo{
+		IP:        middleware.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// writeThrottleError answers delayed or locked accounts with 429 and a
+// Retry-After header. It reports whether err was such an error.
+func writeThrottleError(w http.ResponseWriter, err error) bool {
+	var throttle *lockout.ThrottleError
+	if !errors.As(err, &throttle) {
+		return false
+	}
+	seconds := int(math.Ceil(throttle.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	message := "Too many failed login attempts, try again later"
+	if throttle.Locked {
+		message = "Account temporarily locked, try again later"
+	}
+	utils.WriteError(w, http.StatusTooManyRequests, message)
+	return true
+}
@@ -1,6 +1,51 @@
 package auth
 
+// ClientInfo describes the device signing in
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionCookie switches the response to cookie mode: the tokens are set as
+// HttpOnly cookies instead of being returned in the body
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email         string `json:"email"`
+	Password      string `json:"password"`
+	SessionCookie bool   `json:"session_cookie"`
+}
+
+// RefreshRequest may leave RefreshToken empty to use the refresh cookie
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse is returned by every endpoint that signs a user in. In cookie
+// mode the tokens are omitted and CSRFToken is set instead.
+type TokenResponse struct {
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// LoginResponse carries either the signed tokens or, for users with
+// two-factor authentication, a challenge to exchange at /auth/login/verify
+type LoginResponse struct {
+	*TokenResponse
+	MFARequired        bool   `json:"mfa_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	ChallengeExpiresIn int    `json:"challenge_expires_in,omitempty"`
+}
+
+// ExchangeTicketRequest redeems the ticket an external login redirected with
+type ExchangeTicketRequest struct {
+	Ticket        string `json:"ticket"`
+	SessionCookie bool   `json:"session_cookie"`
+}
+
+type VerifyLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+	SessionCookie  bool   `json:"session_cookie"`
 }
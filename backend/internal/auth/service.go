@@ -1,37 +1,222 @@
 package auth
 
 import (
+	"context"
 	"errors"
+	"strconv"
 
-	"github.com/alexedwards/argon2id"
-	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/lockout"
+	"github.com/othersidedrl/portfolio/backend/internal/session"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"github.com/othersidedrl/portfolio/backend/internal/user"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
 )
 
 // Service contains the business logic for auth
 type Service struct {
-	jwt *utils.JWTService
-	cfg *config.Config
+	jwt      *utils.JWTService
+	tokens   token.Store
+	users    *user.Service
+	lockout  lockout.Store
+	sessions *session.Service
 }
 
-func NewService(jwt *utils.JWTService, cfg *config.Config) *Service {
+func NewService(jwt *utils.JWTService, tokens token.Store, users *user.Service, lockout lockout.Store, sessions *session.Service) *Service {
 	return &Service{
-		jwt: jwt,
-		cfg: cfg,
+		jwt:      jwt,
+		tokens:   tokens,
+		users:    users,
+		lockout:  lockout,
+		sessions: sessions,
 	}
 }
 
-// Login checks the credentials and returns a token
-func (s *Service) Login(email, password string) (string, error) {
-	// Check against config credentials
-	if email != s.cfg.AdminEmail {
-		return "", errors.New("Unauthorized")
+// JWKS returns the public keys that verify access tokens
+func (s *Service) JWKS() utils.JWKSet {
+	return s.jwt.Keys().JWKS()
+}
+
+// Login checks the credentials and starts a new token family.
+// Users with two-factor authentication get a challenge instead of tokens.
+// Failed attempts are counted per account and eventually lock it.
+func (s *Service) Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResponse, error) {
+	if err := s.lockout.Check(ctx, email); err != nil {
+		return nil, err
+	}
+
+	u, err := s.users.Authenticate(ctx, email, password)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			if lockErr := s.lockout.RecordFailure(ctx, email, client.IP); lockErr != nil {
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+
+	if !u.TOTPEnabled {
+		if err := s.lockout.Reset(ctx, u.Email); err != nil {
+			return nil, err
+		}
+	}
+	return s.completeFirstFactor(ctx, u.ID, string(u.Role), u.TOTPEnabled, client)
+}
+
+// ExchangeLoginTicket signs in a user who authenticated with an external
+// identity provider. Two-factor authentication still applies.
+func (s *Service) ExchangeLoginTicket(ctx context.Context, ticket string, client ClientInfo) (*LoginResponse, error) {
+	userID, err := s.tokens.ConsumeLoginTicket(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return nil, token.ErrInvalidLoginTicket
+	}
+	u, err := s.users.GetUser(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, token.ErrInvalidLoginTicket
+		}
+		return nil, err
+	}
+	return s.completeFirstFactor(ctx, u.ID, string(u.Role), u.TOTPEnabled, client)
+}
+
+// completeFirstFactor starts a session, or issues a two-factor challenge for
+// users who enabled TOTP
+func (s *Service) completeFirstFactor(ctx context.Context, userID uint, role string, totpEnabled bool, client ClientInfo) (*LoginResponse, error) {
+	if totpEnabled {
+		challenge, err := s.tokens.IssueChallenge(ctx, strconv.FormatUint(uint64(userID), 10))
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResponse{
+			MFARequired:        true,
+			ChallengeToken:     challenge,
+			ChallengeExpiresIn: int(token.ChallengeTTL.Seconds()),
+		}, nil
+	}
+
+	tokens, err := s.startSession(ctx, userID, role, client)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{TokenResponse: tokens}, nil
+}
+
+// VerifyLogin exchanges a login challenge and a second factor for tokens.
+// Wrong codes count towards the same lockout as wrong passwords.
+func (s *Service) VerifyLogin(ctx context.Context, req *VerifyLoginRequest, client ClientInfo) (*TokenResponse, error) {
+	userID, err := s.tokens.ChallengeUser(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return nil, token.ErrInvalidChallenge
+	}
+	u, err := s.users.GetUser(ctx, uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.lockout.Check(ctx, u.Email); err != nil {
+		return nil, err
+	}
+
+	if err := s.users.VerifySecondFactor(ctx, uint(id), req.Code, req.RecoveryCode); err != nil {
+		if errors.Is(err, user.ErrInvalidTOTPCode) {
+			if lockErr := s.lockout.RecordFailure(ctx, u.Email, client.IP); lockErr != nil {
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+	if err := s.tokens.CompleteChallenge(ctx, req.ChallengeToken); err != nil {
+		return nil, err
+	}
+	if err := s.lockout.Reset(ctx, u.Email); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, u.ID, string(u.Role), client)
+}
+
+// startSession begins a new token family for a fully authenticated user and
+// records it as a session
+func (s *Service) startSession(ctx context.Context, userID uint, role string, client ClientInfo) (*TokenResponse, error) {
+	family, err := token.NewFamily()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessions.StartSession(ctx, userID, family, client.UserAgent, client.IP); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, strconv.FormatUint(uint64(userID), 10), role, family)
+}
+
+// Refresh rotates a refresh token and returns a new token pair
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	record, err := s.tokens.ConsumeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, token.ErrRefreshTokenReused) && record != nil {
+			if endErr := s.sessions.EndSession(ctx, record.Family); endErr != nil {
+				return nil, endErr
+			}
+		}
+		return nil, err
+	}
+
+	// Re-read the user so role changes and deletions take effect on refresh
+	id, err := strconv.ParseUint(record.UserID, 10, 64)
+	if err != nil {
+		return nil, token.ErrInvalidRefreshToken
+	}
+	u, err := s.users.GetUser(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			s.sessions.EndSession(ctx, record.Family)
+			return nil, token.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if err := s.sessions.ExtendSession(ctx, record.Family); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, record.UserID, string(u.Role), record.Family)
+}
+
+// Logout revokes the presented access token and ends its session
+func (s *Service) Logout(ctx context.Context, claims *utils.JWTClaims) error {
+	if claims.ExpiresAt != nil {
+		if err := s.tokens.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+	if claims.Family != "" {
+		return s.sessions.EndSession(ctx, claims.Family)
+	}
+	return nil
+}
+
+func (s *Service) issueTokens(ctx context.Context, userID, role, family string) (*TokenResponse, error) {
+	accessToken, err := s.jwt.GenerateToken(userID, role, family)
+	if err != nil {
+		return nil, err
 	}
 
-	match, err := argon2id.ComparePasswordAndHash(password, s.cfg.AdminPasswordHash)
-	if err != nil || !match {
-		return "", errors.New("Unauthorized")
+	refreshToken, err := s.tokens.IssueRefreshToken(ctx, userID, family)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.jwt.GenerateToken(s.cfg.AdminID)
+	return &TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.jwt.TTL().Seconds()),
+	}, nil
 }
@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// lruSweepInterval is how often an LRUStore drops expired entries, locks
+// that expired unreleased and the keys registered at least that long ago
+// whose response was never stored, like an error page. It is well above how
+// long a render may take.
+const lruSweepInterval = time.Minute
+
+// LRUStore keeps entries in the memory of this instance, up to maxBytes of
+// values, evicting the least recently used first. Entries also expire with
+// their TTL. It is safe for concurrent use.
+type LRUStore struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	order    *list.List // of *lruEntry, most recently used first
+	entries  map[string]*list.Element
+	tags     map[Tag]map[string]struct{}
+	keyTags  map[string][]Tag
+	pending  map[string]time.Time // keys registered without an entry, and when
+	locks    map[string]*lruLock
+	swept    time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type lruLock struct {
+	expiresAt time.Time
+}
+
+func NewLRUStore(maxBytes int) *LRUStore {
+	return &LRUStore{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+		tags:     map[Tag]map[string]struct{}{},
+		keyTags:  map[string][]Tag{},
+		pending:  map[string]time.Time{},
+		locks:    map[string]*lruLock{},
+	}
+}
+
+func (s *LRUStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	entry := elem.Value.(*lruEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		s.remove(elem)
+		return nil, ErrMiss
+	}
+	s.order.MoveToFront(elem)
+	return entry.value, nil
+}
+
+// Set stores a copy of value. A value larger than the whole store is not
+// kept, and its key is dropped from its tags.
+func (s *LRUStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Replacing an entry keeps the tags its key was registered under
+	if elem, ok := s.entries[key]; ok {
+		s.unlink(elem)
+	}
+	if ttl <= 0 || len(value) > s.maxBytes {
+		s.forget(key)
+		return nil
+	}
+	delete(s.pending, key)
+
+	entry := &lruEntry{
+		key:       key,
+		value:     append([]byte(nil), value...),
+		expiresAt: time.Now().Add(ttl),
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	s.size += len(entry.value)
+	for s.size > s.maxBytes {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// remove drops an evicted or expired entry and forgets the tags of its key
+func (s *LRUStore) remove(elem *list.Element) {
+	s.forget(s.unlink(elem))
+}
+
+func (s *LRUStore) unlink(elem *list.Element) string {
+	entry := s.order.Remove(elem).(*lruEntry)
+	delete(s.entries, entry.key)
+	s.size -= len(entry.value)
+	return entry.key
+}
+
+// Delete drops the entries of keys, as an invalidation does
+func (s *LRUStore) Delete(ctx context.Context, keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := s.entries[key]; ok {
+			s.remove(elem)
+		}
+	}
+}
+
+func (s *LRUStore) Lock(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if held, ok := s.locks[key]; ok && now.Before(held.expiresAt) {
+		return nil, ErrLocked
+	}
+	lock := &lruLock{expiresAt: now.Add(ttl)}
+	s.locks[key] = lock
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.locks[key] == lock {
+			delete(s.locks, key)
+		}
+	}, nil
+}
+
+// Register adds key to tags. Until an entry is stored under key, it is only
+// kept for lruSweepInterval.
+func (s *LRUStore) Register(ctx context.Context, key string, tags ...Tag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if _, ok := s.entries[key]; !ok {
+		s.pending[key] = now
+	}
+
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = map[string]struct{}{}
+			s.tags[tag] = keys
+		}
+		if _, ok := keys[key]; !ok {
+			keys[key] = struct{}{}
+			s.keyTags[key] = append(s.keyTags[key], tag)
+		}
+	}
+	return nil
+}
+
+func (s *LRUStore) Registered(ctx context.Context, key string, tags ...Tag) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		if _, ok := s.tags[tag][key]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *LRUStore) Invalidate(ctx context.Context, tags ...Tag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if elem, ok := s.entries[key]; ok {
+				s.unlink(elem)
+			}
+			s.forget(key)
+		}
+		delete(s.tags, tag)
+	}
+	return nil
+}
+
+// forget drops key from every tag it was registered under
+func (s *LRUStore) forget(key string) {
+	for _, tag := range s.keyTags[key] {
+		delete(s.tags[tag], key)
+	}
+	delete(s.keyTags, key)
+	delete(s.pending, key)
+}
+
+// sweep drops, at most once per lruSweepInterval, the entries that expired
+// unread, the registrations never followed by an entry and the locks never
+// released
+func (s *LRUStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < lruSweepInterval {
+		return
+	}
+	s.swept = now
+	for elem := s.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if !now.Before(elem.Value.(*lruEntry).expiresAt) {
+			s.remove(elem)
+		}
+		elem = prev
+	}
+	for key, registeredAt := range s.pending {
+		if now.Sub(registeredAt) >= lruSweepInterval {
+			s.forget(key)
+		}
+	}
+	for key, lock := range s.locks {
+		if !now.Before(lock.expiresAt) {
+			delete(s.locks, key)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustGet(t *testing.T, s Store, key string) string {
+	t.Helper()
+	value, err := s.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	return string(value)
+}
+
+func assertMiss(t *testing.T, s Store, key string) {
+	t.Helper()
+	if value, err := s.Get(context.Background(), key); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(%s) = %q, %v, want ErrMiss", key, value, err)
+	}
+}
+
+func assertRegistered(t *testing.T, s Store, key string, tag Tag, want bool) {
+	t.Helper()
+	got, err := s.Registered(context.Background(), key, tag)
+	if err != nil {
+		t.Fatalf("Registered(%s, %s): %v", key, tag, err)
+	}
+	if got != want {
+		t.Errorf("Registered(%s, %s) = %v, want %v", key, tag, got, want)
+	}
+}
+
+func TestLRUStoreEvictsLeastRecentlyUsedBytes(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(10)
+
+	s.Register(ctx, "b", TagHero)
+	s.Set(ctx, "a", []byte("aaaa"), time.Minute)
+	s.Set(ctx, "b", []byte("bbbb"), time.Minute)
+	mustGet(t, s, "a")
+	s.Set(ctx, "c", []byte("cccc"), time.Minute)
+
+	if got := mustGet(t, s, "a"); got != "aaaa" {
+		t.Errorf("a = %q, want aaaa", got)
+	}
+	if got := mustGet(t, s, "c"); got != "cccc" {
+		t.Errorf("c = %q, want cccc", got)
+	}
+	assertMiss(t, s, "b")
+	assertRegistered(t, s, "b", TagHero, false)
+	if s.size != 8 {
+		t.Errorf("size = %d bytes, want 8", s.size)
+	}
+
+	// A value larger than the whole store is not kept
+	s.Register(ctx, "huge", TagHero)
+	s.Set(ctx, "huge", make([]byte, 11), time.Minute)
+	assertMiss(t, s, "huge")
+	assertRegistered(t, s, "huge", TagHero, false)
+	if got := mustGet(t, s, "a"); got != "aaaa" {
+		t.Errorf("a = %q after an oversized value, want aaaa", got)
+	}
+}
+
+func TestLRUStoreReplacingKeepsSizeAndTags(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(10)
+
+	s.Register(ctx, "a", TagHero)
+	s.Set(ctx, "a", []byte("aaaaaaaa"), time.Minute)
+	s.Set(ctx, "a", []byte("a"), time.Minute)
+
+	if got := mustGet(t, s, "a"); got != "a" || s.size != 1 {
+		t.Errorf("a = %q with size %d, want a with size 1", got, s.size)
+	}
+	assertRegistered(t, s, "a", TagHero, true)
+}
+
+func TestLRUStoreCopiesValues(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(10)
+
+	value := []byte("aaaa")
+	s.Set(ctx, "a", value, time.Minute)
+	value[0] = 'b'
+	if got := mustGet(t, s, "a"); got != "aaaa" {
+		t.Errorf("a = %q after changing the stored slice, want aaaa", got)
+	}
+}
+
+func TestLRUStoreExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(100)
+
+	s.Register(ctx, "short", TagHero)
+	s.Set(ctx, "short", []byte("short"), 20*time.Millisecond)
+	s.Set(ctx, "long", []byte("long"), time.Minute)
+	s.Set(ctx, "none", []byte("none"), 0)
+
+	mustGet(t, s, "short")
+	assertMiss(t, s, "none")
+	time.Sleep(30 * time.Millisecond)
+
+	assertMiss(t, s, "short")
+	assertRegistered(t, s, "short", TagHero, false)
+	if got := mustGet(t, s, "long"); got != "long" {
+		t.Errorf("long = %q, want long", got)
+	}
+	if s.size != len("long") {
+		t.Errorf("size = %d bytes, want only the live entry", s.size)
+	}
+}
+
+func TestLRUStoreInvalidatesTags(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(100)
+
+	s.Register(ctx, "hero", TagHero)
+	s.Register(ctx, "home", TagHero, TagProjects)
+	s.Register(ctx, "projects", TagProjects)
+	for _, key := range []string{"hero", "home", "projects"} {
+		s.Set(ctx, key, []byte(key), time.Minute)
+	}
+
+	if err := s.Invalidate(ctx, TagHero); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	assertMiss(t, s, "hero")
+	assertMiss(t, s, "home")
+	assertRegistered(t, s, "home", TagProjects, false)
+	if got := mustGet(t, s, "projects"); got != "projects" {
+		t.Errorf("projects = %q, want it kept", got)
+	}
+	assertRegistered(t, s, "projects", TagProjects, true)
+
+	// Deleting keys, as an invalidation from another instance does, also
+	// drops their registrations
+	s.Delete(ctx, "projects")
+	assertMiss(t, s, "projects")
+	assertRegistered(t, s, "projects", TagProjects, false)
+}
+
+func TestLRUStoreLocks(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(100)
+
+	unlock, err := s.Lock(ctx, "render", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, err := s.Lock(ctx, "render", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("second Lock error = %v, want ErrLocked", err)
+	}
+	if _, err := s.Lock(ctx, "other", time.Minute); err != nil {
+		t.Errorf("Lock of another key: %v", err)
+	}
+	unlock()
+	unlock, err = s.Lock(ctx, "render", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+
+	// An expired lock can be taken over, and the old holder's unlock then
+	// leaves the new lock alone
+	time.Sleep(30 * time.Millisecond)
+	if _, err := s.Lock(ctx, "render", time.Minute); err != nil {
+		t.Fatalf("Lock after expiry: %v", err)
+	}
+	unlock()
+	if _, err := s.Lock(ctx, "render", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("Lock after a stale unlock error = %v, want ErrLocked", err)
+	}
+}
+
+func TestLRUStoreSweepsPendingKeysAndLocks(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(100)
+
+	// Keys registered without an entry, like an error page, and locks never
+	// released would otherwise pile up
+	s.Register(ctx, "failed", TagHero)
+	s.Register(ctx, "stored", TagHero)
+	s.Set(ctx, "stored", []byte("stored"), time.Minute)
+	if _, err := s.Lock(ctx, "abandoned", time.Millisecond); err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// Nothing is swept before lruSweepInterval passed
+	s.Register(ctx, "fresh", TagHero)
+	if _, ok := s.pending["failed"]; !ok {
+		t.Error("a registration was swept before lruSweepInterval passed")
+	}
+	if _, ok := s.locks["abandoned"]; !ok {
+		t.Error("a lock was swept before lruSweepInterval passed")
+	}
+
+	// Age everything past the interval, as if a minute went by
+	ago := time.Now().Add(-lruSweepInterval)
+	s.swept = ago
+	s.pending["failed"] = ago
+	if _, err := s.Lock(ctx, "next", time.Minute); err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+
+	assertRegistered(t, s, "failed", TagHero, false)
+	if _, ok := s.keyTags["failed"]; ok {
+		t.Error("the tags of a swept registration were kept")
+	}
+	if _, ok := s.locks["abandoned"]; ok {
+		t.Error("an expired lock was not swept")
+	}
+	assertRegistered(t, s, "stored", TagHero, true)
+	assertRegistered(t, s, "fresh", TagHero, true)
+	if _, ok := s.locks["next"]; !ok {
+		t.Error("a held lock was swept")
+	}
+	if len(s.pending) != 1 {
+		t.Errorf("pending = %v, want only the fresh registration", s.pending)
+	}
+}
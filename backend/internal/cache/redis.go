@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// tagSetTTL bounds how long a tag set outlives its last registration.
+	// It has to be longer than any response is cached, or an entry could
+	// outlive the set that finds it.
+	tagSetTTL = 24 * time.Hour
+	// invalidationChannel carries the invalidated tags to every instance, so
+	// they can drop the copies they keep in memory
+	invalidationChannel = "cache_invalidations"
+)
+
+// releaseLock deletes a lock only if it is still the caller's, so a lock
+// that expired and was taken over is left alone
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// invalidation is what is announced on invalidationChannel: the tags and the
+// keys that were registered under them. Instances also drop the keys, since
+// the copies they took from Redis were never registered in their memory.
+type invalidation struct {
+	Tags []Tag    `json:"tags"`
+	Keys []string `json:"keys"`
+}
+
+// RedisStore keeps entries in Redis, shared by every instance, with a Redis
+// set per tag holding the keys registered under it
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func tagKey(tag Tag) string {
+	return "cache_tag:" + string(tag)
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *RedisStore) Lock(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+	token := make([]byte, 16)
+	rand.Read(token)
+	value := hex.EncodeToString(token)
+
+	acquired, err := s.client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrLocked
+	}
+	return func() {
+		if err := releaseLock.Run(ctx, s.client, []string{key}, value).Err(); err != nil {
+			logger.Warn("Failed to release cache lock", "key", key, "error", err)
+		}
+	}, nil
+}
+
+func (s *RedisStore) Register(ctx context.Context, key string, tags ...Tag) error {
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagKey(tag), key)
+			pipe.Expire(ctx, tagKey(tag), tagSetTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) Registered(ctx context.Context, key string, tags ...Tag) (bool, error) {
+	cmds := make([]*redis.BoolCmd, len(tags))
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, tag := range tags {
+			cmds[i] = pipe.SIsMember(ctx, tagKey(tag), key)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, cmd := range cmds {
+		if !cmd.Val() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Invalidate deletes the entries registered under tags and announces them on
+// invalidationChannel. Only the members it read are removed from a set, so a
+// response registered meanwhile stays findable.
+func (s *RedisStore) Invalidate(ctx context.Context, tags ...Tag) error {
+	announced := invalidation{Tags: tags, Keys: []string{}}
+	for _, tag := range tags {
+		keys, err := s.client.SMembers(ctx, tagKey(tag)).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		members := make([]interface{}, len(keys))
+		for i, key := range keys {
+			members[i] = key
+		}
+		_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, keys...)
+			pipe.SRem(ctx, tagKey(tag), members...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		announced.Keys = append(announced.Keys, keys...)
+		logger.Debug("Invalidated cache tag", "tag", tag, "keys", len(keys))
+	}
+
+	message, err := json.Marshal(announced)
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, invalidationChannel, message).Err()
+}
+
+// Subscribe calls fn with the tags every instance invalidates, and the keys
+// that were registered under them, until ctx is cancelled. The subscription
+// is restored after Redis comes back.
+func (s *RedisStore) Subscribe(ctx context.Context, fn func(tags []Tag, keys []string)) {
+	sub := s.client.Subscribe(ctx, invalidationChannel)
+	defer sub.Close()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var announced invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &announced); err != nil {
+				logger.Warn("Ignoring malformed cache invalidation", "payload", msg.Payload)
+				continue
+			}
+			fn(announced.Tags, announced.Keys)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newRedis starts an in-process Redis and a client for it that fails fast
+// once it is closed
+func newRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:        server.Addr(),
+		MaxRetries:  -1,
+		DialTimeout: 100 * time.Millisecond,
+	})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisStoreExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	server, client := newRedis(t)
+	s := NewRedisStore(client)
+
+	if err := s.Set(ctx, "hero", []byte("hero"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got := mustGet(t, s, "hero"); got != "hero" {
+		t.Errorf("hero = %q, want hero", got)
+	}
+	server.FastForward(time.Minute)
+	assertMiss(t, s, "hero")
+}
+
+func TestRedisStoreInvalidatesTags(t *testing.T) {
+	ctx := context.Background()
+	_, client := newRedis(t)
+	s := NewRedisStore(client)
+
+	s.Register(ctx, "hero", TagHero)
+	s.Register(ctx, "home", TagHero, TagProjects)
+	s.Register(ctx, "projects", TagProjects)
+	for _, key := range []string{"hero", "home", "projects"} {
+		s.Set(ctx, key, []byte(key), time.Minute)
+	}
+
+	if err := s.Invalidate(ctx, TagHero); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	assertMiss(t, s, "hero")
+	assertMiss(t, s, "home")
+	assertRegistered(t, s, "hero", TagHero, false)
+	if got := mustGet(t, s, "projects"); got != "projects" {
+		t.Errorf("projects = %q, want it kept", got)
+	}
+	assertRegistered(t, s, "projects", TagProjects, true)
+}
+
+func TestRedisStoreLocks(t *testing.T) {
+	ctx := context.Background()
+	server, client := newRedis(t)
+	s := NewRedisStore(client)
+
+	unlock, err := s.Lock(ctx, "render", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, err := s.Lock(ctx, "render", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("second Lock error = %v, want ErrLocked", err)
+	}
+	unlock()
+	unlock, err = s.Lock(ctx, "render", time.Second)
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+
+	// An expired lock can be taken over, and the old holder's unlock then
+	// leaves the new lock alone
+	server.FastForward(time.Second)
+	if _, err := s.Lock(ctx, "render", time.Minute); err != nil {
+		t.Fatalf("Lock after expiry: %v", err)
+	}
+	unlock()
+	if _, err := s.Lock(ctx, "render", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("Lock after a stale unlock error = %v, want ErrLocked", err)
+	}
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrMiss is returned by Get for a key that is not cached
+	ErrMiss = errors.New("cache miss")
+	// ErrLocked is returned by Lock while someone else holds the lock
+	ErrLocked = errors.New("cache key is locked")
+)
+
+// Cache backends, picked with CACHE_BACKEND
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+	BackendTiered = "tiered"
+	BackendNone   = "none"
+)
+
+// Store keeps cached responses and the tags they are registered under
+type Store interface {
+	// Get returns the value of key, or ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Lock takes the lock named key for at most ttl, or returns ErrLocked
+	// if it is held. unlock releases it only if it is still the caller's.
+	Lock(ctx context.Context, key string, ttl time.Duration) (unlock func(), err error)
+	// Register adds key to each of tags. It is called before the entry is
+	// stored, so no entry exists that an invalidation cannot find.
+	Register(ctx context.Context, key string, tags ...Tag) error
+	// Registered reports whether key is still in each of tags, that is
+	// whether no invalidation dropped it since it was registered
+	Registered(ctx context.Context, key string, tags ...Tag) (bool, error)
+	// Invalidate deletes the entries registered under tags
+	Invalidate(ctx context.Context, tags ...Tag) error
+}
+
+// UsesRedis reports whether backend keeps entries in Redis. Only then does
+// the API connect to Redis, which also holds the sign-in state.
+func UsesRedis(backend string) bool {
+	return backend == BackendRedis || backend == BackendTiered
+}
+
+// NewStore creates the store cfg.CacheBackend names. The Redis backends use
+// client, which may be unreachable: the tiered store then serves from memory.
+func NewStore(cfg *config.Config, client *redis.Client) (Store, error) {
+	switch cfg.CacheBackend {
+	case BackendRedis:
+		return NewRedisStore(client), nil
+	case BackendMemory:
+		return NewLRUStore(cfg.CacheMaxBytes), nil
+	case BackendTiered:
+		return NewTieredStore(NewLRUStore(cfg.CacheMaxBytes), NewRedisStore(client), cfg.CacheLocalTTL), nil
+	case BackendNone:
+		return NopStore{}, nil
+	}
+	return nil, fmt.Errorf("unknown cache backend %q", cfg.CacheBackend)
+}
+
+// NewInvalidator invalidates tags in store, logging a failure
+func NewInvalidator(store Store) Invalidator {
+	return func(ctx context.Context, tags ...Tag) {
+		if err := store.Invalidate(ctx, tags...); err != nil {
+			logger.Warn("Failed to invalidate cache tags", "tags", tags, "error", err)
+		}
+	}
+}
+
+// NopStore caches nothing
+type NopStore struct{}
+
+func (NopStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, ErrMiss
+}
+
+func (NopStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (NopStore) Lock(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+	return func() {}, nil
+}
+
+func (NopStore) Register(ctx context.Context, key string, tags ...Tag) error {
+	return nil
+}
+
+func (NopStore) Registered(ctx context.Context, key string, tags ...Tag) (bool, error) {
+	return true, nil
+}
+
+func (NopStore) Invalidate(ctx context.Context, tags ...Tag) error {
+	return nil
+}
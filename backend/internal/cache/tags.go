@@ -0,0 +1,38 @@
+// Package cache stores cached public responses, in Redis, in memory or both,
+// and names the content they show with tags, so a write can drop every
+// cached response showing what it changed without knowing the routes or keys
+// they are cached under
+package cache
+
+import (
+	"context"
+)
+
+// Tag names a piece of public content a cached response shows
+type Tag string
+
+const (
+	TagHero                Tag = "hero"
+	TagAbout               Tag = "about"
+	TagSkills              Tag = "skills"
+	TagCareers             Tag = "careers"
+	TagProjects            Tag = "projects"
+	TagProjectPage         Tag = "project_page"
+	TagTestimonyPage       Tag = "testimony_page"
+	TagTestimoniesApproved Tag = "testimonies:approved"
+)
+
+// Tags lists every tag
+var Tags = []Tag{
+	TagHero, TagAbout, TagSkills, TagCareers,
+	TagProjects, TagProjectPage, TagTestimonyPage, TagTestimoniesApproved,
+}
+
+// Invalidator drops the cached responses carrying any of tags. Services call
+// it after a successful write; a failure is logged rather than failing the
+// write, and the entries then expire with their TTL.
+type Invalidator func(ctx context.Context, tags ...Tag)
+
+// Nop is an Invalidator for runs without response caches, like filling in
+// missing pages at boot
+func Nop(ctx context.Context, tags ...Tag) {}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+)
+
+// TieredStore fronts Redis with an LRU in the memory of each instance. Reads
+// are served from memory when they can, and while Redis is unreachable the
+// store keeps working from memory alone. Local copies live for at most
+// localTTL, which bounds how long an instance may miss an invalidation
+// published while it could not hear it.
+type TieredStore struct {
+	local    *LRUStore
+	remote   *RedisStore
+	localTTL time.Duration
+}
+
+func NewTieredStore(local *LRUStore, remote *RedisStore, localTTL time.Duration) *TieredStore {
+	return &TieredStore{local: local, remote: remote, localTTL: localTTL}
+}
+
+func (s *TieredStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := s.local.Get(ctx, key); err == nil {
+		return value, nil
+	}
+	value, err := s.remote.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrMiss) {
+			logger.Debug("Cache read from Redis failed", "key", key, "error", err)
+		}
+		return nil, ErrMiss
+	}
+	s.local.Set(ctx, key, value, s.localTTL)
+	return value, nil
+}
+
+func (s *TieredStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.local.Set(ctx, key, value, min(ttl, s.localTTL))
+	if err := s.remote.Set(ctx, key, value, ttl); err != nil {
+		logger.Debug("Cache write to Redis failed", "key", key, "error", err)
+	}
+	return nil
+}
+
+// Lock takes the lock in Redis, so one instance renders for all of them, and
+// falls back to a local lock while Redis is unreachable
+func (s *TieredStore) Lock(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+	unlock, err := s.remote.Lock(ctx, key, ttl)
+	if err == nil || errors.Is(err, ErrLocked) {
+		return unlock, err
+	}
+	logger.Debug("Cache lock in Redis failed", "key", key, "error", err)
+	return s.local.Lock(ctx, key, ttl)
+}
+
+func (s *TieredStore) Register(ctx context.Context, key string, tags ...Tag) error {
+	s.local.Register(ctx, key, tags...)
+	if err := s.remote.Register(ctx, key, tags...); err != nil {
+		logger.Debug("Cache tag registration in Redis failed", "key", key, "error", err)
+	}
+	return nil
+}
+
+// Registered asks Redis too, since another instance may have invalidated the
+// key; while Redis is unreachable only this instance's invalidations count
+func (s *TieredStore) Registered(ctx context.Context, key string, tags ...Tag) (bool, error) {
+	if ok, _ := s.local.Registered(ctx, key, tags...); !ok {
+		return false, nil
+	}
+	ok, err := s.remote.Registered(ctx, key, tags...)
+	if err != nil {
+		logger.Debug("Cache tag lookup in Redis failed", "key", key, "error", err)
+		return true, nil
+	}
+	return ok, nil
+}
+
+// Invalidate drops the local entries and those in Redis, which announces the
+// tags to the other instances
+func (s *TieredStore) Invalidate(ctx context.Context, tags ...Tag) error {
+	s.local.Invalidate(ctx, tags...)
+	return s.remote.Invalidate(ctx, tags...)
+}
+
+// Run drops the local entries other instances invalidate until ctx is
+// cancelled
+func (s *TieredStore) Run(ctx context.Context) {
+	s.remote.Subscribe(ctx, func(tags []Tag, keys []string) {
+		s.local.Invalidate(ctx, tags...)
+		s.local.Delete(ctx, keys...)
+	})
+}
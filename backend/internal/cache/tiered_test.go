@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTieredStoreReadsThroughToRedis(t *testing.T) {
+	ctx := context.Background()
+	_, client := newRedis(t)
+	remote := NewRedisStore(client)
+	s := NewTieredStore(NewLRUStore(100), remote, time.Minute)
+
+	// An entry another instance stored is copied into memory when read
+	remote.Set(ctx, "hero", []byte("hero"), time.Minute)
+	if got := mustGet(t, s, "hero"); got != "hero" {
+		t.Errorf("hero = %q, want hero", got)
+	}
+	if got := mustGet(t, s.local, "hero"); got != "hero" {
+		t.Errorf("local hero = %q, want the copy read from Redis", got)
+	}
+
+	// A write reaches both tiers
+	s.Set(ctx, "about", []byte("about"), time.Minute)
+	if got := mustGet(t, remote, "about"); got != "about" {
+		t.Errorf("remote about = %q, want about", got)
+	}
+}
+
+func TestTieredStoreBoundsLocalTTL(t *testing.T) {
+	ctx := context.Background()
+	_, client := newRedis(t)
+	s := NewTieredStore(NewLRUStore(100), NewRedisStore(client), 20*time.Millisecond)
+
+	s.Set(ctx, "hero", []byte("hero"), time.Hour)
+	time.Sleep(30 * time.Millisecond)
+	assertMiss(t, s.local, "hero")
+	if got := mustGet(t, s, "hero"); got != "hero" {
+		t.Errorf("hero = %q, want it read again from Redis", got)
+	}
+}
+
+func TestTieredStoreFallsBackToMemoryWhenRedisFails(t *testing.T) {
+	ctx := context.Background()
+	server, client := newRedis(t)
+	s := NewTieredStore(NewLRUStore(100), NewRedisStore(client), time.Minute)
+
+	s.Register(ctx, "hero", TagHero)
+	s.Set(ctx, "hero", []byte("hero"), time.Minute)
+	server.Close()
+
+	if got := mustGet(t, s, "hero"); got != "hero" {
+		t.Errorf("hero = %q, want it served from memory", got)
+	}
+	assertMiss(t, s, "missing")
+
+	if err := s.Register(ctx, "about", TagAbout); err != nil {
+		t.Errorf("Register: %v", err)
+	}
+	if err := s.Set(ctx, "about", []byte("about"), time.Minute); err != nil {
+		t.Errorf("Set: %v", err)
+	}
+	if got := mustGet(t, s, "about"); got != "about" {
+		t.Errorf("about = %q, want it stored in memory", got)
+	}
+
+	// Only this instance's invalidations count while Redis is unreachable
+	assertRegistered(t, s, "hero", TagHero, true)
+	if err := s.Invalidate(ctx, TagHero); err == nil {
+		t.Error("Invalidate reported success without Redis")
+	}
+	assertMiss(t, s, "hero")
+	assertRegistered(t, s, "hero", TagHero, false)
+
+	// Locks are taken in memory instead
+	unlock, err := s.Lock(ctx, "render", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, err := s.Lock(ctx, "render", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("second Lock error = %v, want ErrLocked", err)
+	}
+	unlock()
+	if _, err := s.Lock(ctx, "render", time.Minute); err != nil {
+		t.Errorf("Lock after unlock: %v", err)
+	}
+}
+
+func TestTieredStoreLocksAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	_, client := newRedis(t)
+	a := NewTieredStore(NewLRUStore(100), NewRedisStore(client), time.Minute)
+	b := NewTieredStore(NewLRUStore(100), NewRedisStore(client), time.Minute)
+
+	unlock, err := a.Lock(ctx, "render", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, err := b.Lock(ctx, "render", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("Lock on another instance error = %v, want ErrLocked", err)
+	}
+	unlock()
+	if _, err := b.Lock(ctx, "render", time.Minute); err != nil {
+		t.Errorf("Lock on another instance after unlock: %v", err)
+	}
+}
+
+func TestTieredStoreDropsCopiesOtherInstancesInvalidate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, client := newRedis(t)
+	a := NewTieredStore(NewLRUStore(100), NewRedisStore(client), time.Minute)
+	b := NewTieredStore(NewLRUStore(100), NewRedisStore(client), time.Minute)
+	go b.Run(ctx)
+	for server.PubSubNumSub(invalidationChannel)[invalidationChannel] == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	a.Register(ctx, "hero", TagHero)
+	a.Set(ctx, "hero", []byte("hero"), time.Minute)
+	mustGet(t, b, "hero")
+
+	if err := a.Invalidate(ctx, TagHero); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := b.local.Get(ctx, "hero"); errors.Is(err, ErrMiss) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the copy on another instance outlived the invalidation")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assertMiss(t, b, "hero")
+}
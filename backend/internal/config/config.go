@@ -2,33 +2,122 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/othersidedrl/portfolio/backend/internal/retry"
 )
 
 type Config struct {
 	Port           string
 	AllowedOrigins []string
+	// TrustedProxies are the peers whose X-Forwarded-For and X-Real-IP
+	// headers are believed; without any, the client IP is the peer address
+	TrustedProxies []netip.Prefix
 
-	// Database
+	// Database. DBDriver is "postgres" or "sqlite"; SQLite keeps everything in
+	// the single file at SQLitePath
+	DBDriver   string
 	DBHost     string
 	DBPort     string
 	DBUser     string
 	DBPassword string
 	DBName     string
+	SQLitePath string
+	// DBAutoMigrate applies pending migrations on boot
+	DBAutoMigrate bool
+	// Postgres connection pool. SQLite always uses a single connection.
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+	DBConnectTimeout  time.Duration
+	// TrashRetention is how long deleted content can be restored before it is
+	// purged for good
+	TrashRetention time.Duration
+	// PreviewTokenTTL is how long a draft preview link works
+	PreviewTokenTTL time.Duration
+	// DraftSchedulerInterval is how often scheduled drafts are published
+	DraftSchedulerInterval time.Duration
+	// Browsers and CDNs may reuse a public response for PublicMaxAge, and
+	// serve it stale for PublicStaleWhileRevalidate more while revalidating
+	PublicMaxAge               time.Duration
+	PublicStaleWhileRevalidate time.Duration
+	// Cached public responses are fresh for CachePageTTL or CacheSectionTTL,
+	// then served stale for CacheStaleTTL while one request refreshes them.
+	// A 404 is cached for CacheNegativeTTL. Either of the last two may be 0
+	// to turn it off.
+	CachePageTTL     time.Duration
+	CacheSectionTTL  time.Duration
+	CacheStaleTTL    time.Duration
+	CacheNegativeTTL time.Duration
+	// CacheBackend is where responses are cached: "redis", "memory" (an LRU
+	// of CacheMaxBytes in each instance), "tiered" (that LRU in front of
+	// Redis, keeping copies for CacheLocalTTL) or "none". With "redis" and
+	// "tiered" authentication keeps its state in Redis too and the API needs
+	// it to start; with "memory" and "none" it never connects to Redis and
+	// keeps that state in memory, which suits a single instance only.
+	CacheBackend  string
+	CacheMaxBytes int
+	CacheLocalTTL time.Duration
 
-	// Redis
-	RedisHost     string
-	RedisPort     string
-	RedisPassword string
+	// Redis. A pool size of 0 keeps go-redis' default of 10 per CPU.
+	RedisHost         string
+	RedisPort         string
+	RedisPassword     string
+	RedisPoolSize     int
+	RedisMinIdleConns int
+	RedisDialTimeout  time.Duration
+	RedisReadTimeout  time.Duration
+	RedisWriteTimeout time.Duration
+
+	// Connecting to the database and Redis at startup is attempted up to
+	// ConnectMaxAttempts times, waiting ConnectInitialBackoff and then twice
+	// as long each time up to ConnectMaxBackoff, so the API waits for them to
+	// boot instead of crash-looping
+	ConnectMaxAttempts    int
+	ConnectInitialBackoff time.Duration
+	ConnectMaxBackoff     time.Duration
+	// ShutdownTimeout bounds how long shutdown waits for in-flight requests
+	// and background work
+	ShutdownTimeout time.Duration
 
 	// Auth
-	JWTSecret         string
+	JWTSecret string
+	// JWTSigningKeyFile is a PEM Ed25519 or RSA private key; when set, tokens
+	// are signed with it instead of JWTSecret. JWTVerificationKeyFiles lists
+	// retired keys that are still accepted while rotating.
+	JWTSigningKeyFile       string
+	JWTVerificationKeyFiles []string
+	// JWTSecretAcceptedUntil keeps HS256 tokens signed with JWTSecret valid
+	// next to a signing key file while migrating to it. Without it the
+	// secret is ignored once a signing key file is set.
+	JWTSecretAcceptedUntil time.Time
+	AccessTokenTTL         time.Duration
+	RefreshTokenTTL        time.Duration
+	TOTPIssuer             string
+	// Failed logins per account before it is locked for LoginLockoutDuration
+	LoginMaxFailures     int
+	LoginFailureWindow   time.Duration
+	LoginLockoutDuration time.Duration
+	// Session cookies, used when a client logs in with session_cookie. Off by
+	// default, leaving bearer tokens as the only way to authenticate.
+	CookieAuth     bool
+	CookieDomain   string
+	CookieSecure   bool
+	CookieSameSite string
+	// OIDCProviders are the external identity providers offered for login;
+	// OIDCLoginRedirectURL is the CMS page that finishes the login
+	OIDCProviders        []OIDCProviderConfig
+	OIDCLoginRedirectURL string
+	// AdminEmail and AdminPasswordHash seed the first owner account when the
+	// users table is empty
 	AdminEmail        string
 	AdminPasswordHash string
-	AdminID           string
 
 	// External Services
 	CloudinaryName      string
@@ -37,7 +126,100 @@ type Config struct {
 	OpenRouterAPIKey    string
 }
 
+// OIDCProviderConfig configures one OpenID Connect provider. RedirectURL is
+// this API's callback, e.g. https://api.example.com/api/v1/auth/oidc/google/callback
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// Load loads and validates the configuration of the API server
 func Load() (*Config, error) {
+	cfg := load()
+
+	// Process Allowed Origins
+	allowedOrigins := getEnv("ALLOWED_ORIGINS", "http://localhost:3000")
+	cfg.AllowedOrigins = strings.Split(allowedOrigins, ",")
+
+	// Process trusted proxies, as CIDRs or single addresses
+	if proxies := getEnv("TRUSTED_PROXIES", ""); proxies != "" {
+		for _, proxy := range strings.Split(proxies, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy == "" {
+				continue
+			}
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				addr, addrErr := netip.ParseAddr(proxy)
+				if addrErr != nil {
+					return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: use a CIDR or an IP address", proxy)
+				}
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+			cfg.TrustedProxies = append(cfg.TrustedProxies, prefix.Masked())
+		}
+	}
+
+	// Process verification keys
+	if files := getEnv("JWT_VERIFICATION_KEY_FILES", ""); files != "" {
+		for _, file := range strings.Split(files, ",") {
+			if file = strings.TrimSpace(file); file != "" {
+				cfg.JWTVerificationKeyFiles = append(cfg.JWTVerificationKeyFiles, file)
+			}
+		}
+	}
+
+	if until := getEnv("JWT_SECRET_ACCEPTED_UNTIL", ""); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_SECRET_ACCEPTED_UNTIL %q: use RFC 3339, e.g. 2026-12-31T00:00:00Z", until)
+		}
+		cfg.JWTSecretAcceptedUntil = t
+	}
+
+	// Process OIDC providers, e.g. OIDC_PROVIDERS=google with OIDC_GOOGLE_ISSUER,
+	// OIDC_GOOGLE_CLIENT_ID, OIDC_GOOGLE_CLIENT_SECRET and OIDC_GOOGLE_REDIRECT_URL
+	if names := getEnv("OIDC_PROVIDERS", ""); names != "" {
+		for _, name := range strings.Split(names, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			prefix := "OIDC_" + strings.ToUpper(name) + "_"
+			cfg.OIDCProviders = append(cfg.OIDCProviders, OIDCProviderConfig{
+				Name:         name,
+				Issuer:       getEnv(prefix+"ISSUER", ""),
+				ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+				ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+				RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			})
+		}
+	}
+
+	// Validate required fields
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadDatabase loads the configuration of the migrate and seed commands.
+// They need the database settings, and Redis and the cache backend to drop
+// cached responses, so settings only the server uses are neither parsed nor
+// required.
+func LoadDatabase() (*Config, error) {
+	cfg := load()
+	if err := cfg.validateDatabase(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// load reads the settings that need no further processing
+func load() *Config {
 	// Load .env file if it exists (ignore error if missing, rely on env vars)
 	_ = godotenv.Load()
 
@@ -45,22 +227,68 @@ func Load() (*Config, error) {
 		Port: getEnv("PORT", "8080"),
 
 		// Database
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("POSTGRES_USER", "postgres"),
-		DBPassword: getEnv("POSTGRES_PASSWORD", ""),
-		DBName:     getEnv("POSTGRES_DB", "postgres"),
+		DBDriver:       strings.ToLower(getEnv("DB_DRIVER", "postgres")),
+		DBHost:         getEnv("DB_HOST", "localhost"),
+		DBPort:         getEnv("DB_PORT", "5432"),
+		DBUser:         getEnv("POSTGRES_USER", "postgres"),
+		DBPassword:     getEnv("POSTGRES_PASSWORD", ""),
+		DBName:         getEnv("POSTGRES_DB", "postgres"),
+		SQLitePath:     getEnv("SQLITE_PATH", "portfolio.db"),
+		DBAutoMigrate:  getBool("DB_AUTO_MIGRATE", true),
+		TrashRetention: getDuration("TRASH_RETENTION", 30*24*time.Hour),
+
+		DBMaxOpenConns:    getInt("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:    getInt("DB_MAX_IDLE_CONNS", 5),
+		DBConnMaxLifetime: getDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		DBConnMaxIdleTime: getDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		DBConnectTimeout:  getDuration("DB_CONNECT_TIMEOUT", 5*time.Second),
+
+		PreviewTokenTTL:        getDuration("PREVIEW_TOKEN_TTL", time.Hour),
+		DraftSchedulerInterval: getDuration("DRAFT_SCHEDULER_INTERVAL", time.Minute),
+
+		PublicMaxAge:               getDuration("PUBLIC_MAX_AGE", 30*time.Second),
+		PublicStaleWhileRevalidate: getDuration("PUBLIC_STALE_WHILE_REVALIDATE", 5*time.Minute),
+		CachePageTTL:               getDuration("CACHE_PAGE_TTL", time.Hour),
+		CacheSectionTTL:            getDuration("CACHE_SECTION_TTL", 30*time.Minute),
+		CacheStaleTTL:              getDurationOrZero("CACHE_STALE_TTL", 5*time.Minute),
+		CacheNegativeTTL:           getDurationOrZero("CACHE_NEGATIVE_TTL", 30*time.Second),
+		CacheBackend:               getEnv("CACHE_BACKEND", "tiered"),
+		CacheMaxBytes:              getInt("CACHE_MAX_BYTES", 64<<20),
+		CacheLocalTTL:              getDuration("CACHE_LOCAL_TTL", time.Minute),
 
 		// Redis
 		RedisHost:     getEnv("REDIS_HOST", "localhost"),
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 
+		RedisPoolSize:     getInt("REDIS_POOL_SIZE", 0),
+		RedisMinIdleConns: getInt("REDIS_MIN_IDLE_CONNS", 0),
+		RedisDialTimeout:  getDuration("REDIS_DIAL_TIMEOUT", 5*time.Second),
+		RedisReadTimeout:  getDuration("REDIS_READ_TIMEOUT", 3*time.Second),
+		RedisWriteTimeout: getDuration("REDIS_WRITE_TIMEOUT", 3*time.Second),
+
+		// Startup and shutdown
+		ConnectMaxAttempts:    getInt("CONNECT_MAX_ATTEMPTS", 10),
+		ConnectInitialBackoff: getDuration("CONNECT_INITIAL_BACKOFF", 500*time.Millisecond),
+		ConnectMaxBackoff:     getDuration("CONNECT_MAX_BACKOFF", 10*time.Second),
+		ShutdownTimeout:       getDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+
 		// Auth
-		JWTSecret:         getEnv("JWT_SECRET", ""),
-		AdminEmail:        getEnv("ADMIN_EMAIL", ""),
-		AdminPasswordHash: getEnv("ADMIN_PASSWORD_HASH", ""),
-		AdminID:           getEnv("ADMIN_ID", ""),
+		JWTSecret:            getEnv("JWT_SECRET", ""),
+		JWTSigningKeyFile:    getEnv("JWT_SIGNING_KEY_FILE", ""),
+		AccessTokenTTL:       getDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:      getDuration("REFRESH_TOKEN_TTL", 7*24*time.Hour),
+		TOTPIssuer:           getEnv("TOTP_ISSUER", "Portfolio CMS"),
+		LoginMaxFailures:     getInt("LOGIN_MAX_FAILURES", 10),
+		LoginFailureWindow:   getDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		LoginLockoutDuration: getDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		CookieAuth:           getBool("COOKIE_AUTH", false),
+		CookieDomain:         getEnv("COOKIE_DOMAIN", ""),
+		CookieSecure:         getBool("COOKIE_SECURE", true),
+		CookieSameSite:       getEnv("COOKIE_SAMESITE", "strict"),
+		OIDCLoginRedirectURL: getEnv("OIDC_LOGIN_REDIRECT_URL", "http://localhost:3001/login/oidc"),
+		AdminEmail:           getEnv("ADMIN_EMAIL", ""),
+		AdminPasswordHash:    getEnv("ADMIN_PASSWORD_HASH", ""),
 
 		// External
 		CloudinaryName:      getEnv("CLOUDINARY_NAME", ""),
@@ -68,23 +296,32 @@ func Load() (*Config, error) {
 		CloudinaryAPISecret: getEnv("CLOUDINARY_APISECRET", ""),
 		OpenRouterAPIKey:    getEnv("OPENROUTER_APIKEY", ""),
 	}
+	return cfg
+}
 
-	// Process Allowed Origins
-	allowedOrigins := getEnv("ALLOWED_ORIGINS", "http://localhost:3000")
-	cfg.AllowedOrigins = strings.Split(allowedOrigins, ",")
+func (c *Config) validate() error {
+	if err := c.validateDatabase(); err != nil {
+		return err
+	}
 
-	// Validate required fields
-	if err := cfg.validate(); err != nil {
-		return nil, err
+	if c.JWTSecret == "" && c.JWTSigningKeyFile == "" {
+		return fmt.Errorf("missing required environment variable: JWT_SECRET or JWT_SIGNING_KEY_FILE")
 	}
 
-	return cfg, nil
-}
+	for _, p := range c.OIDCProviders {
+		prefix := "OIDC_" + strings.ToUpper(p.Name) + "_"
+		for key, val := range map[string]string{
+			prefix + "ISSUER":       p.Issuer,
+			prefix + "CLIENT_ID":    p.ClientID,
+			prefix + "REDIRECT_URL": p.RedirectURL,
+		} {
+			if val == "" {
+				return fmt.Errorf("missing required environment variable: %s", key)
+			}
+		}
+	}
 
-func (c *Config) validate() error {
 	required := map[string]string{
-		"JWT_SECRET":           c.JWTSecret,
-		"ADMIN_EMAIL":          c.AdminEmail,
 		"CLOUDINARY_NAME":      c.CloudinaryName,
 		"CLOUDINARY_APIKEY":    c.CloudinaryAPIKey,
 		"CLOUDINARY_APISECRET": c.CloudinaryAPISecret,
@@ -99,9 +336,77 @@ func (c *Config) validate() error {
 	return nil
 }
 
+func (c *Config) validateDatabase() error {
+	if c.DBDriver != "postgres" && c.DBDriver != "sqlite" {
+		return fmt.Errorf("unsupported DB_DRIVER %q: use postgres or sqlite", c.DBDriver)
+	}
+	return nil
+}
+
+// ConnectRetry is the retry policy for connecting at startup
+func (c *Config) ConnectRetry() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:    c.ConnectMaxAttempts,
+		InitialBackoff: c.ConnectInitialBackoff,
+		MaxBackoff:     c.ConnectMaxBackoff,
+	}
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return fallback
 }
+
+// getDuration parses a Go duration string (e.g. "15m", "168h")
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// getInt parses a positive integer
+// getDurationOrZero is getDuration for settings where 0 turns something off
+func getDurationOrZero(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return fallback
+	}
+	return d
+}
+
+func getInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+// getBool parses a boolean such as "true", "0" or "false"
+func getBool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
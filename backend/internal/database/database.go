@@ -1,127 +1,152 @@
 package database
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"strings"
 
+	"github.com/glebarez/sqlite"
 	"github.com/othersidedrl/portfolio/backend/internal/config"
 	"github.com/othersidedrl/portfolio/backend/internal/logger"
 	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/retry"
+	"github.com/othersidedrl/portfolio/backend/internal/seed"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectDB(cfg *config.Config) *gorm.DB {
+// Open connects to the configured database without touching the schema
+func Open(cfg *config.Config) (*gorm.DB, error) {
+	if cfg.DBDriver == "sqlite" {
+		return openSQLite(cfg.SQLitePath)
+	}
+
 	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable connect_timeout=%d",
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName,
+		max(int(cfg.DBConnectTimeout.Seconds()), 1),
 	)
-
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
-		logger.Error("Failed to connect to DB", "error", err)
-		log.Fatal("Failed to connect to DB:", err)
+		// gorm opens the pool before it pings, so a failed ping leaves it open
+		if db != nil {
+			Close(db)
+		}
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+	return db, nil
+}
 
-	// Create PostgreSQL enum types (must exist before AutoMigrate)
-	enumTypes := []string{
-		`DO $$ BEGIN CREATE TYPE career_type AS ENUM ('Education', 'Job'); EXCEPTION WHEN duplicate_object THEN null; END $$;`,
-		`DO $$ BEGIN CREATE TYPE skill_level AS ENUM ('Beginner', 'Intermediate', 'Advanced', 'Expert'); EXCEPTION WHEN duplicate_object THEN null; END $$;`,
-		`DO $$ BEGIN CREATE TYPE category AS ENUM ('Backend', 'Frontend', 'Other'); EXCEPTION WHEN duplicate_object THEN null; END $$;`,
-		`DO $$ BEGIN CREATE TYPE project_type AS ENUM ('Web', 'Mobile', 'Machine Learning'); EXCEPTION WHEN duplicate_object THEN null; END $$;`,
-		`DO $$ BEGIN CREATE TYPE contribution_type AS ENUM ('Personal', 'Team'); EXCEPTION WHEN duplicate_object THEN null; END $$;`,
+// Connect opens the database like Open, retrying with backoff while it is
+// unreachable, e.g. while Postgres is still starting
+func Connect(ctx context.Context, cfg *config.Config) (*gorm.DB, error) {
+	var db *gorm.DB
+	err := retry.Do(ctx, cfg.ConnectRetry(), "connect to DB", func(context.Context) error {
+		var err error
+		db, err = Open(cfg)
+		return err
+	})
+	return db, err
+}
+
+// Close closes the connection pool of db
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
 	}
-	for _, sql := range enumTypes {
-		if err := db.Exec(sql).Error; err != nil {
-			logger.Warn("Failed to create enum type", "error", err)
-		}
+	return sqlDB.Close()
+}
+
+// openSQLite opens the database file with foreign keys on and WAL journaling.
+// SQLite allows one writer at a time, so the pool is limited to a single
+// connection instead of surfacing "database is locked" errors.
+func openSQLite(path string) (*gorm.DB, error) {
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
 	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db, nil
+}
 
-	// Auto-migrate tables
-	err = db.AutoMigrate(
-		&models.HeroPage{},
-		&models.AboutPage{},
-		&models.TechnicalSkills{},
-		&models.CareerJourney{},
-		&models.TestimonyPage{},
-		&models.Testimony{},
-		&models.ProjectPage{},
-		&models.Project{},
-		// &models.User{},
-		// You can add more models here
-	)
+// ConnectDB connects, retrying until the database is up, applies pending
+// migrations unless DB_AUTO_MIGRATE is off, and seeds initial content. The
+// pool is closed again if any step fails.
+func ConnectDB(ctx context.Context, cfg *config.Config) (*gorm.DB, error) {
+	db, err := Connect(ctx, cfg)
 	if err != nil {
-		logger.Error("Auto migration failed", "error", err)
-		log.Fatal("Auto migration failed:", err)
+		return nil, err
 	}
 
-	// Seed database with initial data
-	seedDatabase(db)
+	if err := prepare(ctx, db, cfg); err != nil {
+		Close(db)
+		return nil, err
+	}
 
 	logger.Info("Connected and migrated DB successfully")
-	return db
+	return db, nil
 }
 
-func seedDatabase(db *gorm.DB) {
-	// Seed Hero Page
-	var hero models.HeroPage
-	if err := db.First(&hero).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			db.Create(&models.HeroPage{
-				Name:        "Hero Name",
-				Title:       "Hero Title",
-				Subtitle:    "Hero Subtitle",
-				Rank:        "Hero Rank",
-				ResumeLink:  "",
-				ContactLink: "",
-			})
-			logger.Info("Seeded Hero Page")
+func prepare(ctx context.Context, db *gorm.DB, cfg *config.Config) error {
+	if cfg.DBAutoMigrate {
+		migrator, err := NewMigrator(db)
+		if err != nil {
+			return fmt.Errorf("load migrations: %w", err)
 		}
-	}
-
-	// Seed About Page
-	var about models.AboutPage
-	if err := db.First(&about).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			db.Create(&models.AboutPage{
-				Description:  "About Description",
-				Card1Title:   "Card 1",
-				Card1Desc:    "Description",
-				Card2Title:   "Card 2",
-				Card2Desc:    "Description",
-				Card3Title:   "Card 3",
-				Card3Desc:    "Description",
-				Card4Title:   "Card 4",
-				Card4Desc:    "Description",
-				GithubLink:   "",
-				LinkedinLink: "",
-				Available:    true,
-			})
-			logger.Info("Seeded About Page")
+		if _, err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("migrate: %w", err)
 		}
 	}
 
-	// Seed Project Page
-	var project models.ProjectPage
-	if err := db.First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			db.Create(&models.ProjectPage{
-				Title:       "Projects",
-				Description: "My Projects",
-			})
-			logger.Info("Seeded Project Page")
-		}
+	// Seed database with initial data
+	return seedDatabase(ctx, db, cfg)
+}
+
+// seedDatabase fills in missing pages from the default fixture and creates
+// the first owner account
+func seedDatabase(ctx context.Context, db *gorm.DB, cfg *config.Config) error {
+	defaults, err := seed.Builtin(seed.Default)
+	if err != nil {
+		return fmt.Errorf("load default fixture: %w", err)
+	}
+	if err := seed.EnsurePages(ctx, db, defaults); err != nil {
+		return fmt.Errorf("seed pages: %w", err)
 	}
 
-	// Seed Testimony Page
-	var testimony models.TestimonyPage
-	if err := db.First(&testimony).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			db.Create(&models.TestimonyPage{
-				Title:       "Testimonials",
-				Description: "What people say",
-			})
-			logger.Info("Seeded Testimony Page")
+	// Seed the first owner from the legacy admin credentials
+	var userCount int64
+	if err := db.WithContext(ctx).Model(&models.User{}).Count(&userCount).Error; err != nil {
+		return fmt.Errorf("count users: %w", err)
+	}
+	if userCount == 0 {
+		if cfg.AdminEmail != "" && cfg.AdminPasswordHash != "" {
+			err := db.WithContext(ctx).Create(&models.User{
+				Email:        strings.ToLower(strings.TrimSpace(cfg.AdminEmail)),
+				Name:         "Owner",
+				PasswordHash: cfg.AdminPasswordHash,
+				Role:         models.RoleOwner,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("seed owner account: %w", err)
+			}
+			logger.Info("Seeded owner account", "email", cfg.AdminEmail)
+		} else {
+			logger.Warn("No users exist and ADMIN_EMAIL/ADMIN_PASSWORD_HASH are not set; nobody can log in to the CMS")
 		}
 	}
+	return nil
 }
@@ -0,0 +1,264 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the pg_advisory_lock key held while migrating, so that
+// several instances booting at once apply each migration exactly once
+const migrationLockID int64 = 7_391_208_432
+
+// schemaMigrationsDDL creates the bookkeeping table for each dialect
+var schemaMigrationsDDL = map[string]string{
+	"postgres": `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`,
+	"sqlite": `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at datetime NOT NULL
+	)`,
+}
+
+// Migration is one versioned schema change read from
+// <version>_<name>.up.sql and <version>_<name>.down.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator applies embedded migrations and records them in schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	dialect    string
+	migrations []Migration
+}
+
+// NewMigrator loads the embedded migrations written for db's dialect
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	dialect := db.Dialector.Name()
+	if _, ok := schemaMigrationsDDL[dialect]; !ok {
+		return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+	}
+	sub, err := fs.Sub(migrationFiles, "migrations/"+dialect)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// LoadMigrations reads and orders the migrations in fsys. Every version needs
+// an up script; down scripts are optional but required to roll back.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || path.Ext(file) != ".sql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(file, ".sql")
+		direction := path.Ext(base)
+		base = strings.TrimSuffix(base, direction)
+		versionStr, name, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if !ok || err != nil || (direction != ".up" && direction != ".down") {
+			return nil, fmt.Errorf("invalid migration file name %q", file)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, name)
+		}
+		if direction == ".up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns how many ran
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				return tx.Exec(
+					"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+					migration.Version, migration.Name, time.Now(),
+				).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			logger.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the most recent steps applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack := 0
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && rolledBack < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be rolled back: no down script", migration.Version, migration.Name)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rolling back %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			logger.Info("Rolled back migration", "version", migration.Version, "name", migration.Name)
+			rolledBack++
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status lists every known migration and when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if appliedAt, ok := done[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock runs fn on a single connection holding the migration lock.
+// Session-level advisory locks belong to a connection, so the pool must not
+// hand out a different one in between. SQLite has no advisory locks, so fn
+// runs in a transaction holding the database's write lock instead, and the
+// migrations' own transactions become savepoints within it.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if m.dialect == "postgres" {
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+				return fmt.Errorf("acquiring migration lock: %w", err)
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+		}
+
+		if err := conn.Exec(schemaMigrationsDDL[m.dialect]).Error; err != nil {
+			return err
+		}
+		if m.dialect != "sqlite" {
+			return fn(conn)
+		}
+		return conn.Transaction(func(tx *gorm.DB) error {
+			// Starting with a write takes the write lock up front, as BEGIN
+			// IMMEDIATE would, waiting out any other runner for busy_timeout
+			if err := tx.Exec("UPDATE schema_migrations SET version = version WHERE 0 = 1").Error; err != nil {
+				return fmt.Errorf("acquiring migration lock: %w", err)
+			}
+			return fn(tx)
+		})
+	})
+}
+
+func appliedVersions(conn *gorm.DB) (map[int64]time.Time, error) {
+	var rows []struct {
+		Version   int64
+		AppliedAt time.Time
+	}
+	if err := conn.Raw("SELECT version, applied_at FROM schema_migrations").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row.AppliedAt
+	}
+	return done, nil
+}
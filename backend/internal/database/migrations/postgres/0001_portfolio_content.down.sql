@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS project_pages;
+DROP TABLE IF EXISTS testimonies;
+DROP TABLE IF EXISTS testimony_pages;
+DROP TABLE IF EXISTS career_journeys;
+DROP TABLE IF EXISTS technical_skills;
+DROP TABLE IF EXISTS about_pages;
+DROP TABLE IF EXISTS hero_pages;
+
+DROP TYPE IF EXISTS contribution_type;
+DROP TYPE IF EXISTS project_type;
+DROP TYPE IF EXISTS category;
+DROP TYPE IF EXISTS skill_level;
+DROP TYPE IF EXISTS career_type;
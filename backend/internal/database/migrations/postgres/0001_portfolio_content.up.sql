@@ -0,0 +1,127 @@
+-- Portfolio content tables. Databases created by the old AutoMigrate boot
+-- already have these objects, so every statement is idempotent.
+
+DO $$ BEGIN CREATE TYPE career_type AS ENUM ('Education', 'Job'); EXCEPTION WHEN duplicate_object THEN null; END $$;
+DO $$ BEGIN CREATE TYPE skill_level AS ENUM ('Beginner', 'Intermediate', 'Advanced', 'Expert'); EXCEPTION WHEN duplicate_object THEN null; END $$;
+DO $$ BEGIN CREATE TYPE category AS ENUM ('Backend', 'Frontend', 'Other'); EXCEPTION WHEN duplicate_object THEN null; END $$;
+DO $$ BEGIN CREATE TYPE project_type AS ENUM ('Web', 'Mobile', 'Machine Learning'); EXCEPTION WHEN duplicate_object THEN null; END $$;
+DO $$ BEGIN CREATE TYPE contribution_type AS ENUM ('Personal', 'Team'); EXCEPTION WHEN duplicate_object THEN null; END $$;
+
+CREATE TABLE IF NOT EXISTS hero_pages (
+    id           bigserial PRIMARY KEY,
+    name         text,
+    rank         text,
+    title        text,
+    subtitle     text,
+    resume_link  text,
+    contact_link text,
+    image_url1   text,
+    image_url2   text,
+    image_url3   text,
+    image_url4   text,
+    hobbies      text[],
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_hero_pages_deleted_at ON hero_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS about_pages (
+    id            bigserial PRIMARY KEY,
+    description   text,
+    card1_title   text,
+    card1_desc    text,
+    card2_title   text,
+    card2_desc    text,
+    card3_title   text,
+    card3_desc    text,
+    card4_title   text,
+    card4_desc    text,
+    github_link   text,
+    linkedin_link text,
+    available     boolean,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_about_pages_deleted_at ON about_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS technical_skills (
+    id                 bigserial PRIMARY KEY,
+    name               text,
+    description        text,
+    specialities       text[],
+    level              skill_level,
+    category           category,
+    year_of_experience bigint,
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    deleted_at         timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_technical_skills_deleted_at ON technical_skills (deleted_at);
+
+CREATE TABLE IF NOT EXISTS career_journeys (
+    id          bigserial PRIMARY KEY,
+    started_at  text,
+    ended_at    text,
+    title       text,
+    affiliation text,
+    description text,
+    location    text,
+    type        career_type,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_career_journeys_deleted_at ON career_journeys (deleted_at);
+
+CREATE TABLE IF NOT EXISTS testimony_pages (
+    id          bigserial PRIMARY KEY,
+    title       text,
+    description text,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_testimony_pages_deleted_at ON testimony_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS testimonies (
+    id          bigserial PRIMARY KEY,
+    name        text,
+    profile_url text,
+    affiliation text,
+    rating      bigint,
+    description text,
+    ai_summary  text,
+    approved    boolean,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_testimonies_deleted_at ON testimonies (deleted_at);
+
+CREATE TABLE IF NOT EXISTS project_pages (
+    id          bigserial PRIMARY KEY,
+    title       text,
+    description text,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_project_pages_deleted_at ON project_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id           bigserial PRIMARY KEY,
+    name         text,
+    image_urls   text[],
+    description  text,
+    tech_stack   text[],
+    github_link  text,
+    type         project_type,
+    contribution contribution_type,
+    project_link text,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects (deleted_at);
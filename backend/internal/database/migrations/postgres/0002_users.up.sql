@@ -0,0 +1,29 @@
+-- CMS accounts and their TOTP recovery codes
+
+DO $$ BEGIN CREATE TYPE user_role AS ENUM ('owner', 'editor', 'moderator'); EXCEPTION WHEN duplicate_object THEN null; END $$;
+
+CREATE TABLE IF NOT EXISTS users (
+    id                bigserial PRIMARY KEY,
+    email             text NOT NULL,
+    name              text,
+    password_hash     text NOT NULL,
+    role              user_role NOT NULL,
+    totp_secret       text,
+    totp_enabled      boolean,
+    totp_last_counter bigint,
+    created_at        timestamptz,
+    updated_at        timestamptz,
+    deleted_at        timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    code_hash  text NOT NULL,
+    used_at    timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recovery_codes_code_hash ON recovery_codes (code_hash);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id           text PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    user_agent   text,
+    ip_address   text,
+    last_seen_at timestamptz,
+    expires_at   timestamptz,
+    revoked_at   timestamptz,
+    created_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at);
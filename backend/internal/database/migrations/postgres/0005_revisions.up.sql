@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS revisions (
+    id         bigserial PRIMARY KEY,
+    kind       text NOT NULL,
+    entity_id  bigint NOT NULL,
+    action     text NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'rollback')),
+    author_id  bigint,
+    snapshot   jsonb NOT NULL,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_revisions_kind_entity ON revisions (kind, entity_id, id);
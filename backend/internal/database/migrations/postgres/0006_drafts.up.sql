@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS drafts (
+    id                   bigserial PRIMARY KEY,
+    kind                 text NOT NULL,
+    entity_id            bigint,
+    payload              jsonb NOT NULL,
+    author_id            bigint,
+    publish_at           timestamptz,
+    unpublish_at         timestamptz,
+    published_at         timestamptz,
+    unpublished_at       timestamptz,
+    previous_revision_id bigint REFERENCES revisions (id) ON DELETE SET NULL,
+    created_at           timestamptz,
+    updated_at           timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_drafts_kind ON drafts (kind);
+CREATE INDEX IF NOT EXISTS idx_drafts_publish_at ON drafts (publish_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_drafts_unpublish_at ON drafts (unpublish_at) WHERE unpublished_at IS NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS project_pages;
+DROP TABLE IF EXISTS testimonies;
+DROP TABLE IF EXISTS testimony_pages;
+DROP TABLE IF EXISTS career_journeys;
+DROP TABLE IF EXISTS technical_skills;
+DROP TABLE IF EXISTS about_pages;
+DROP TABLE IF EXISTS hero_pages;
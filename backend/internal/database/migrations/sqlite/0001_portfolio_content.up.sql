@@ -0,0 +1,121 @@
+-- Portfolio content tables. Enums are text columns with CHECK constraints and
+-- lists are JSON arrays stored as text.
+
+CREATE TABLE IF NOT EXISTS hero_pages (
+    id           integer PRIMARY KEY AUTOINCREMENT,
+    name         text,
+    rank         text,
+    title        text,
+    subtitle     text,
+    resume_link  text,
+    contact_link text,
+    image_url1   text,
+    image_url2   text,
+    image_url3   text,
+    image_url4   text,
+    hobbies      text,
+    created_at   datetime,
+    updated_at   datetime,
+    deleted_at   datetime
+);
+CREATE INDEX IF NOT EXISTS idx_hero_pages_deleted_at ON hero_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS about_pages (
+    id            integer PRIMARY KEY AUTOINCREMENT,
+    description   text,
+    card1_title   text,
+    card1_desc    text,
+    card2_title   text,
+    card2_desc    text,
+    card3_title   text,
+    card3_desc    text,
+    card4_title   text,
+    card4_desc    text,
+    github_link   text,
+    linkedin_link text,
+    available     boolean,
+    created_at    datetime,
+    updated_at    datetime,
+    deleted_at    datetime
+);
+CREATE INDEX IF NOT EXISTS idx_about_pages_deleted_at ON about_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS technical_skills (
+    id                 integer PRIMARY KEY AUTOINCREMENT,
+    name               text,
+    description        text,
+    specialities       text,
+    level              text CHECK (level IN ('Beginner', 'Intermediate', 'Advanced', 'Expert')),
+    category           text CHECK (category IN ('Backend', 'Frontend', 'Other')),
+    year_of_experience integer,
+    created_at         datetime,
+    updated_at         datetime,
+    deleted_at         datetime
+);
+CREATE INDEX IF NOT EXISTS idx_technical_skills_deleted_at ON technical_skills (deleted_at);
+
+CREATE TABLE IF NOT EXISTS career_journeys (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    started_at  text,
+    ended_at    text,
+    title       text,
+    affiliation text,
+    description text,
+    location    text,
+    type        text CHECK (type IN ('Education', 'Job')),
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime
+);
+CREATE INDEX IF NOT EXISTS idx_career_journeys_deleted_at ON career_journeys (deleted_at);
+
+CREATE TABLE IF NOT EXISTS testimony_pages (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    title       text,
+    description text,
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime
+);
+CREATE INDEX IF NOT EXISTS idx_testimony_pages_deleted_at ON testimony_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS testimonies (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    name        text,
+    profile_url text,
+    affiliation text,
+    rating      integer,
+    description text,
+    ai_summary  text,
+    approved    boolean,
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime
+);
+CREATE INDEX IF NOT EXISTS idx_testimonies_deleted_at ON testimonies (deleted_at);
+
+CREATE TABLE IF NOT EXISTS project_pages (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    title       text,
+    description text,
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime
+);
+CREATE INDEX IF NOT EXISTS idx_project_pages_deleted_at ON project_pages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id           integer PRIMARY KEY AUTOINCREMENT,
+    name         text,
+    image_urls   text,
+    description  text,
+    tech_stack   text,
+    github_link  text,
+    type         text CHECK (type IN ('Web', 'Mobile', 'Machine Learning')),
+    contribution text CHECK (contribution IN ('Personal', 'Team')),
+    project_link text,
+    created_at   datetime,
+    updated_at   datetime,
+    deleted_at   datetime
+);
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects (deleted_at);
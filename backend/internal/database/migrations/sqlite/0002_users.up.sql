@@ -0,0 +1,27 @@
+-- CMS accounts and their TOTP recovery codes
+
+CREATE TABLE IF NOT EXISTS users (
+    id                integer PRIMARY KEY AUTOINCREMENT,
+    email             text NOT NULL,
+    name              text,
+    password_hash     text NOT NULL,
+    role              text NOT NULL CHECK (role IN ('owner', 'editor', 'moderator')),
+    totp_secret       text,
+    totp_enabled      boolean,
+    totp_last_counter integer,
+    created_at        datetime,
+    updated_at        datetime,
+    deleted_at        datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    user_id    integer NOT NULL,
+    code_hash  text NOT NULL,
+    used_at    datetime,
+    created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recovery_codes_code_hash ON recovery_codes (code_hash);
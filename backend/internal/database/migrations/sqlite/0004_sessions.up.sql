@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id           text PRIMARY KEY,
+    user_id      integer NOT NULL,
+    user_agent   text,
+    ip_address   text,
+    last_seen_at datetime,
+    expires_at   datetime,
+    revoked_at   datetime,
+    created_at   datetime
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS revisions (
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    kind       text NOT NULL,
+    entity_id  integer NOT NULL,
+    action     text NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'rollback')),
+    author_id  integer,
+    snapshot   text NOT NULL,
+    created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_revisions_kind_entity ON revisions (kind, entity_id, id);
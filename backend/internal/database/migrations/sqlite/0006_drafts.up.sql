@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS drafts (
+    id                   integer PRIMARY KEY AUTOINCREMENT,
+    kind                 text NOT NULL,
+    entity_id            integer,
+    payload              text NOT NULL,
+    author_id            integer,
+    publish_at           datetime,
+    unpublish_at         datetime,
+    published_at         datetime,
+    unpublished_at       datetime,
+    previous_revision_id integer REFERENCES revisions (id) ON DELETE SET NULL,
+    created_at           datetime,
+    updated_at           datetime
+);
+CREATE INDEX IF NOT EXISTS idx_drafts_kind ON drafts (kind);
+CREATE INDEX IF NOT EXISTS idx_drafts_publish_at ON drafts (publish_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_drafts_unpublish_at ON drafts (unpublish_at) WHERE unpublished_at IS NULL;
@@ -0,0 +1,237 @@
+package draft
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+// Handler serves the drafts of each kind on their own route, so that routes
+// can require the scope that edits that kind
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetDrafts handles GET /admin/drafts/{kind}
+func (h *Handler) GetDrafts(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := h.service.GetDrafts(r.Context(), kind)
+		if err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"length": len(list.Drafts),
+			"data":   list.Drafts,
+		})
+	}
+}
+
+// GetDraft handles GET /admin/drafts/{kind}/{id}
+func (h *Handler) GetDraft(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		draft, err := h.service.GetDraft(r.Context(), kind, id)
+		if err != nil {
+			writeDraftError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, draft)
+	}
+}
+
+// Create handles POST /admin/drafts/{kind}
+func (h *Handler) Create(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body SaveDraftDto
+		if err := utils.DecodeBody(r, &body); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		draft, err := h.service.Create(r.Context(), kind, &body, author(r))
+		if err != nil {
+			writeDraftError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusCreated, draft)
+	}
+}
+
+// Update handles PATCH /admin/drafts/{kind}/{id}, a JSON merge patch of the
+// draft. Content is merged field by field, and a schedule set to null is
+// cleared.
+func (h *Handler) Update(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		current, err := h.service.GetDraft(r.Context(), kind, id)
+		if err != nil {
+			writeDraftError(w, err)
+			return
+		}
+		body := SaveDraftDto{
+			EntityID:    current.EntityID,
+			Content:     current.Content,
+			PublishAt:   current.PublishAt,
+			UnpublishAt: current.UnpublishAt,
+		}
+		if err := utils.PatchBody(r, &body); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		draft, err := h.service.Update(r.Context(), kind, id, &body, author(r))
+		if err != nil {
+			if middleware.WritePreconditionFailed(w, r, err) {
+				return
+			}
+			writeDraftError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, draft)
+	}
+}
+
+// Delete handles DELETE /admin/drafts/{kind}/{id}
+func (h *Handler) Delete(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		if err := h.service.Delete(r.Context(), kind, id); err != nil {
+			if middleware.WritePreconditionFailed(w, r, err) {
+				return
+			}
+			writeDraftError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Publish handles POST /admin/drafts/{kind}/{id}/publish
+func (h *Handler) Publish(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		draft, err := h.service.Publish(r.Context(), kind, id)
+		if err != nil {
+			if middleware.WritePreconditionFailed(w, r, err) {
+				return
+			}
+			writeDraftError(w, err)
+			return
+		}
+		logger.Info("Published draft", "kind", kind, "draft", id, "by", actor(r))
+		utils.WriteJSON(w, http.StatusOK, draft)
+	}
+}
+
+// Unpublish handles POST /admin/drafts/{kind}/{id}/unpublish
+func (h *Handler) Unpublish(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		draft, err := h.service.Unpublish(r.Context(), kind, id)
+		if err != nil {
+			if middleware.WritePreconditionFailed(w, r, err) {
+				return
+			}
+			writeDraftError(w, err)
+			return
+		}
+		logger.Info("Unpublished draft", "kind", kind, "draft", id, "by", actor(r))
+		utils.WriteJSON(w, http.StatusOK, draft)
+	}
+}
+
+// PreviewToken handles POST /admin/drafts/{kind}/{id}/preview
+func (h *Handler) PreviewToken(kind revision.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		token, err := h.service.PreviewToken(r.Context(), kind, id)
+		if err != nil {
+			writeDraftError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, token)
+	}
+}
+
+// Preview handles GET /preview?token=, the public read of a draft
+func (h *Handler) Preview(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		utils.WriteError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+		return
+	}
+	preview, err := h.service.Preview(r.Context(), token)
+	if err != nil {
+		writeDraftError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, preview)
+}
+
+func writeDraftError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrDraftNotFound), errors.Is(err, ErrEntityNotFound):
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidDraft):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrAlreadyPublished), errors.Is(err, ErrNotPublished):
+		utils.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidToken):
+		utils.WriteError(w, http.StatusUnauthorized, err.Error())
+	default:
+		utils.WriteServerError(w, err)
+	}
+}
+
+func idParam(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid draft ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// author is the signed-in user's ID, or nil for API keys without a user
+func author(r *http.Request) *uint {
+	id, err := strconv.ParseUint(actor(r), 10, 64)
+	if err != nil {
+		return nil
+	}
+	uid := uint(id)
+	return &uid
+}
+
+func actor(r *http.Request) string {
+	if claims := middleware.GetUserFromContext(r.Context()); claims != nil {
+		return claims.Sub
+	}
+	return ""
+}
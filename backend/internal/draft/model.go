@@ -0,0 +1,63 @@
+package draft
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+)
+
+// Status is where a draft is in its lifecycle
+type Status string
+
+const (
+	StatusDraft       Status = "draft"
+	StatusScheduled   Status = "scheduled"
+	StatusPublished   Status = "published"
+	StatusUnpublished Status = "unpublished"
+)
+
+// SaveDraftDto creates a draft, and is what a JSON merge patch of a draft is
+// applied to. Content has the shape of the section's own DTO, e.g. a
+// HeroPageDto for the hero. EntityID picks the item to edit; leave it out for
+// pages and for new items. PublishAt and UnpublishAt are optional and are
+// carried out by the scheduler.
+type SaveDraftDto struct {
+	EntityID    *uint           `json:"entity_id"`
+	Content     json.RawMessage `json:"content"`
+	PublishAt   *time.Time      `json:"publish_at"`
+	UnpublishAt *time.Time      `json:"unpublish_at"`
+}
+
+type DraftDto struct {
+	ID            uint            `json:"id"`
+	Kind          revision.Kind   `json:"kind"`
+	EntityID      *uint           `json:"entity_id"`
+	Content       json.RawMessage `json:"content"`
+	Status        Status          `json:"status"`
+	AuthorID      *uint           `json:"author_id"`
+	PublishAt     *time.Time      `json:"publish_at"`
+	UnpublishAt   *time.Time      `json:"unpublish_at"`
+	PublishedAt   *time.Time      `json:"published_at"`
+	UnpublishedAt *time.Time      `json:"unpublished_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type DraftListDto struct {
+	Drafts []DraftDto `json:"drafts"`
+}
+
+// PreviewTokenDto is handed to the public frontend to render a draft
+type PreviewTokenDto struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PreviewDto is what a preview token unlocks
+type PreviewDto struct {
+	Kind     revision.Kind   `json:"kind"`
+	EntityID *uint           `json:"entity_id"`
+	Content  json.RawMessage `json:"content"`
+	Status   Status          `json:"status"`
+}
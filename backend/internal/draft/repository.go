@@ -0,0 +1,239 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
+	"gorm.io/gorm"
+)
+
+type DraftRepository interface {
+	GetDrafts(ctx context.Context, kind revision.Kind) ([]models.Draft, error)
+	GetDraft(ctx context.Context, kind revision.Kind, id uint) (*models.Draft, error)
+	CreateDraft(ctx context.Context, draft *models.Draft) error
+	UpdateDraft(ctx context.Context, draft *models.Draft) error
+	DeleteDraft(ctx context.Context, kind revision.Kind, id uint) (bool, error)
+	EntityExists(ctx context.Context, kind revision.Kind, id uint) (bool, error)
+	GetDueDrafts(ctx context.Context, now time.Time) (publish, unpublish []models.Draft, err error)
+	Publish(ctx context.Context, draft *models.Draft, now time.Time) (bool, error)
+	Unpublish(ctx context.Context, draft *models.Draft, now time.Time) (bool, error)
+}
+
+type GormDraftRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDraftRepository(db *gorm.DB) *GormDraftRepository {
+	return &GormDraftRepository{db: db}
+}
+
+// GetDrafts lists the drafts of a kind, newest first
+func (r *GormDraftRepository) GetDrafts(ctx context.Context, kind revision.Kind) ([]models.Draft, error) {
+	var drafts []models.Draft
+	err := r.db.WithContext(ctx).Where("kind = ?", kind).Order("id DESC").Find(&drafts).Error
+	return drafts, err
+}
+
+func (r *GormDraftRepository) GetDraft(ctx context.Context, kind revision.Kind, id uint) (*models.Draft, error) {
+	var draft models.Draft
+	if err := r.db.WithContext(ctx).Where("id = ? AND kind = ?", id, kind).First(&draft).Error; err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (r *GormDraftRepository) CreateDraft(ctx context.Context, draft *models.Draft) error {
+	return r.db.WithContext(ctx).Create(draft).Error
+}
+
+// UpdateDraft saves the content and schedule of a draft that is not published
+func (r *GormDraftRepository) UpdateDraft(ctx context.Context, draft *models.Draft) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Draft{}, draft.ID); err != nil {
+			return err
+		}
+		return tx.Model(draft).
+			Where("published_at IS NULL").
+			Select("entity_id", "payload", "author_id", "publish_at", "unpublish_at", "updated_at").
+			Updates(draft).Error
+	})
+}
+
+// DeleteDraft discards a draft; published drafts are kept as a record and
+// report false
+func (r *GormDraftRepository) DeleteDraft(ctx context.Context, kind revision.Kind, id uint) (bool, error) {
+	deleted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Draft{}, id); err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND kind = ? AND published_at IS NULL", id, kind).
+			Delete(&models.Draft{})
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+	return deleted, err
+}
+
+// EntityExists reports whether the live item a draft edits exists
+func (r *GormDraftRepository) EntityExists(ctx context.Context, kind revision.Kind, id uint) (bool, error) {
+	sec, ok := sections[kind]
+	if !ok || !sec.item() {
+		return false, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	var count int64
+	err := r.db.WithContext(ctx).Model(sec.model()).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// GetDueDrafts returns the scheduled drafts whose publish or unpublish time
+// has come
+func (r *GormDraftRepository) GetDueDrafts(ctx context.Context, now time.Time) (publish, unpublish []models.Draft, err error) {
+	db := r.db.WithContext(ctx)
+	err = db.Where("published_at IS NULL AND publish_at IS NOT NULL AND publish_at <= ?", now).
+		Order("publish_at").Find(&publish).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	err = db.Where("published_at IS NOT NULL AND unpublished_at IS NULL AND unpublish_at IS NOT NULL AND unpublish_at <= ?", now).
+		Order("unpublish_at").Find(&unpublish).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return publish, unpublish, nil
+}
+
+// Publish applies the draft to the live content in one transaction. It
+// reports false if the draft was already published, so concurrent schedulers
+// publish a draft only once.
+func (r *GormDraftRepository) Publish(ctx context.Context, draft *models.Draft, now time.Time) (bool, error) {
+	kind := revision.Kind(draft.Kind)
+	sec, ok := sections[kind]
+	if !ok {
+		return false, fmt.Errorf("%w: %q", ErrUnknownKind, draft.Kind)
+	}
+	content, err := sec.decode([]byte(draft.Payload))
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidDraft, err)
+	}
+
+	published := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only the draft is checked against If-Match; the writes publishing
+		// it makes to the live content are not
+		if err := version.LockItem(ctx, tx, &models.Draft{}, draft.ID); err != nil {
+			return err
+		}
+		claim := tx.Model(&models.Draft{}).
+			Where("id = ? AND published_at IS NULL", draft.ID).
+			Update("published_at", now)
+		if claim.Error != nil || claim.RowsAffected == 0 {
+			return claim.Error
+		}
+
+		revisions := revision.NewGormRevisionRepository(tx)
+		newItem := sec.item() && draft.EntityID == nil
+
+		// Remember what was live so unpublishing can bring it back. Content
+		// that was never edited since it was seeded has no revision yet, so
+		// its current state is recorded first.
+		if !newItem {
+			live := sec.model()
+			query := tx.Session(&gorm.Session{NewDB: true})
+			if draft.EntityID != nil {
+				query = query.Where("id = ?", *draft.EntityID)
+			}
+			if err := query.First(live).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrEntityNotFound
+				}
+				return err
+			}
+			prev, err := revisions.GetLatestRevision(ctx, kind, draft.EntityID)
+			if err != nil {
+				return err
+			}
+			if prev == nil {
+				if err := revision.Record(tx, kind, models.RevisionUpdate, live); err != nil {
+					return err
+				}
+				if prev, err = revisions.GetLatestRevision(ctx, kind, draft.EntityID); err != nil {
+					return err
+				}
+			}
+			if prev != nil {
+				draft.PreviousRevisionID = &prev.ID
+			}
+		}
+
+		id, err := sec.apply(ctx, tx, draft.EntityID, content)
+		if err != nil {
+			return err
+		}
+		// A new item is tied to the row it created
+		if newItem {
+			draft.EntityID = &id
+		}
+
+		draft.PublishedAt = &now
+		published = true
+		return tx.Model(draft).
+			Select("entity_id", "previous_revision_id", "published_at").
+			Updates(draft).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return published, nil
+}
+
+// Unpublish reverts a published draft: the revision that was live before it
+// is rolled back, or an item the draft created is moved to the trash. It
+// reports false if the draft is not published or already unpublished.
+func (r *GormDraftRepository) Unpublish(ctx context.Context, draft *models.Draft, now time.Time) (bool, error) {
+	kind := revision.Kind(draft.Kind)
+	sec, ok := sections[kind]
+	if !ok {
+		return false, fmt.Errorf("%w: %q", ErrUnknownKind, draft.Kind)
+	}
+
+	unpublished := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Draft{}, draft.ID); err != nil {
+			return err
+		}
+		claim := tx.Model(&models.Draft{}).
+			Where("id = ? AND published_at IS NOT NULL AND unpublished_at IS NULL", draft.ID).
+			Update("unpublished_at", now)
+		if claim.Error != nil || claim.RowsAffected == 0 {
+			return claim.Error
+		}
+		draft.UnpublishedAt = &now
+		unpublished = true
+
+		switch {
+		case draft.PreviousRevisionID != nil:
+			revisions := revision.NewGormRevisionRepository(tx)
+			prev, err := revisions.GetRevision(ctx, kind, *draft.PreviousRevisionID)
+			if err != nil {
+				return err
+			}
+			return revisions.Rollback(ctx, prev)
+		case sec.item() && draft.EntityID != nil:
+			return sec.remove(ctx, tx, *draft.EntityID)
+		default:
+			logger.Warn("Unpublished draft has no earlier version to restore", "kind", kind, "draft", draft.ID)
+			return nil
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	return unpublished, nil
+}
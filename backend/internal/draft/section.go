@@ -0,0 +1,134 @@
+package draft
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"github.com/othersidedrl/portfolio/backend/internal/hero"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+	"gorm.io/gorm"
+)
+
+// section publishes one kind of content through its feature repository, so
+// publishing records revisions like any other edit. Items can be created or
+// updated; pages always overwrite their single row.
+type section struct {
+	model  func() interface{}
+	decode func(data []byte) (interface{}, error)
+	// apply writes content and returns the id of the item it wrote, which it
+	// creates if entityID is nil; pages return 0
+	apply func(ctx context.Context, tx *gorm.DB, entityID *uint, content interface{}) (uint, error)
+	// remove is set for items and moves an item created by a draft to the
+	// trash on unpublish
+	remove func(ctx context.Context, tx *gorm.DB, id uint) error
+}
+
+func (s section) item() bool {
+	return s.remove != nil
+}
+
+var sections = map[revision.Kind]section{
+	revision.KindHero: {
+		model:  func() interface{} { return &models.HeroPage{} },
+		decode: decodeAs(func(*hero.HeroPageDto) error { return nil }),
+		apply: func(ctx context.Context, tx *gorm.DB, _ *uint, content interface{}) (uint, error) {
+			return 0, hero.NewGormHeroRepository(tx).Update(ctx, content.(*hero.HeroPageDto))
+		},
+	},
+	revision.KindAbout: {
+		model: func() interface{} { return &models.AboutPage{} },
+		decode: decodeAs(func(dto *about.AboutPageDto) error {
+			if len(dto.Cards) != 4 {
+				return errors.New("about must have exactly 4 cards")
+			}
+			return nil
+		}),
+		apply: func(ctx context.Context, tx *gorm.DB, _ *uint, content interface{}) (uint, error) {
+			return 0, about.NewGormAboutRepository(tx).Update(ctx, content.(*about.AboutPageDto))
+		},
+	},
+	revision.KindProjectPage: {
+		model:  func() interface{} { return &models.ProjectPage{} },
+		decode: decodeAs(func(*project.ProjectPageDto) error { return nil }),
+		apply: func(ctx context.Context, tx *gorm.DB, _ *uint, content interface{}) (uint, error) {
+			return 0, project.NewGormProjectRepository(tx).UpdateProjectPage(ctx, content.(*project.ProjectPageDto))
+		},
+	},
+	revision.KindTestimonyPage: {
+		model:  func() interface{} { return &models.TestimonyPage{} },
+		decode: decodeAs(func(*testimony.TestimonyPageDto) error { return nil }),
+		apply: func(ctx context.Context, tx *gorm.DB, _ *uint, content interface{}) (uint, error) {
+			return 0, testimony.NewGormTestimonyRepository(tx).UpdateTestimonyPage(ctx, content.(*testimony.TestimonyPageDto))
+		},
+	},
+	revision.KindSkills: {
+		model:  func() interface{} { return &models.TechnicalSkills{} },
+		decode: decodeAs((*about.SkillItemDto).Validate),
+		apply: func(ctx context.Context, tx *gorm.DB, id *uint, content interface{}) (uint, error) {
+			repo := about.NewGormAboutRepository(tx)
+			dto := content.(*about.SkillItemDto)
+			if id == nil {
+				err := repo.CreateTechnicalSkill(ctx, dto)
+				return dto.ID, err
+			}
+			return *id, repo.UpdateTechnicalSkill(ctx, dto, *id)
+		},
+		remove: func(ctx context.Context, tx *gorm.DB, id uint) error {
+			return about.NewGormAboutRepository(tx).DeleteTechnicalSkill(ctx, id)
+		},
+	},
+	revision.KindCareers: {
+		model:  func() interface{} { return &models.CareerJourney{} },
+		decode: decodeAs((*about.CareerItemDto).Validate),
+		apply: func(ctx context.Context, tx *gorm.DB, id *uint, content interface{}) (uint, error) {
+			repo := about.NewGormAboutRepository(tx)
+			dto := content.(*about.CareerItemDto)
+			if id == nil {
+				err := repo.CreateCareer(ctx, dto)
+				return dto.ID, err
+			}
+			return *id, repo.UpdateCareer(ctx, dto, *id)
+		},
+		remove: func(ctx context.Context, tx *gorm.DB, id uint) error {
+			return about.NewGormAboutRepository(tx).DeleteCareer(ctx, id)
+		},
+	},
+	revision.KindProjects: {
+		model:  func() interface{} { return &models.Project{} },
+		decode: decodeAs((*project.ProjectItemDto).Validate),
+		apply: func(ctx context.Context, tx *gorm.DB, id *uint, content interface{}) (uint, error) {
+			repo := project.NewGormProjectRepository(tx)
+			dto := content.(*project.ProjectItemDto)
+			if id == nil {
+				err := repo.CreateProject(ctx, dto)
+				return uint(dto.ID), err
+			}
+			return *id, repo.UpdateProject(ctx, dto, *id)
+		},
+		remove: func(ctx context.Context, tx *gorm.DB, id uint) error {
+			return project.NewGormProjectRepository(tx).DeleteProject(ctx, id)
+		},
+	},
+}
+
+// decodeAs strictly decodes content into T and validates it
+func decodeAs[T any](validate func(*T) error) func([]byte) (interface{}, error) {
+	return func(data []byte) (interface{}, error) {
+		dto := new(T)
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(dto); err != nil {
+			return nil, err
+		}
+		if err := validate(dto); err != nil {
+			return nil, err
+		}
+		return dto, nil
+	}
+}
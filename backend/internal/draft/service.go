@@ -0,0 +1,320 @@
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownKind      = errors.New("unknown content kind")
+	ErrDraftNotFound    = errors.New("draft not found")
+	ErrInvalidDraft     = errors.New("invalid draft")
+	ErrEntityNotFound   = errors.New("the edited item does not exist")
+	ErrAlreadyPublished = errors.New("draft is already published")
+	ErrNotPublished     = errors.New("draft is not published")
+	ErrInvalidToken     = errors.New("invalid or expired preview token")
+)
+
+type Service struct {
+	repo       DraftRepository
+	jwt        *utils.JWTService
+	previewTTL time.Duration
+	invalidate cache.Invalidator
+}
+
+// NewService signs preview tokens valid for previewTTL and calls invalidate
+// after content of a kind went live or was taken down. Publishing also
+// happens in the scheduler, outside any request.
+func NewService(repo DraftRepository, jwt *utils.JWTService, previewTTL time.Duration, invalidate cache.Invalidator) *Service {
+	return &Service{repo: repo, jwt: jwt, previewTTL: previewTTL, invalidate: invalidate}
+}
+
+// GetDrafts lists the drafts of a kind, newest first
+func (s *Service) GetDrafts(ctx context.Context, kind revision.Kind) (*DraftListDto, error) {
+	drafts, err := s.repo.GetDrafts(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]DraftDto, 0, len(drafts))
+	for _, d := range drafts {
+		items = append(items, toDto(&d))
+	}
+	return &DraftListDto{Drafts: items}, nil
+}
+
+func (s *Service) GetDraft(ctx context.Context, kind revision.Kind, id uint) (*DraftDto, error) {
+	draft, err := s.getDraft(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	dto := toDto(draft)
+	return &dto, nil
+}
+
+// Create saves a new draft by authorID, which may be nil
+func (s *Service) Create(ctx context.Context, kind revision.Kind, dto *SaveDraftDto, authorID *uint) (*DraftDto, error) {
+	draft := &models.Draft{Kind: string(kind), AuthorID: authorID}
+	if err := s.fill(ctx, kind, draft, dto); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateDraft(ctx, draft); err != nil {
+		return nil, err
+	}
+	result := toDto(draft)
+	return &result, nil
+}
+
+// Update replaces the content and schedule of a draft. Published drafts are
+// read-only.
+func (s *Service) Update(ctx context.Context, kind revision.Kind, id uint, dto *SaveDraftDto, authorID *uint) (*DraftDto, error) {
+	draft, err := s.getDraft(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.PublishedAt != nil {
+		return nil, ErrAlreadyPublished
+	}
+	draft.AuthorID = authorID
+	if err := s.fill(ctx, kind, draft, dto); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateDraft(ctx, draft); err != nil {
+		return nil, err
+	}
+	result := toDto(draft)
+	return &result, nil
+}
+
+// Delete discards a draft that was not published
+func (s *Service) Delete(ctx context.Context, kind revision.Kind, id uint) error {
+	draft, err := s.getDraft(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if draft.PublishedAt != nil {
+		return ErrAlreadyPublished
+	}
+	deleted, err := s.repo.DeleteDraft(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAlreadyPublished
+	}
+	return nil
+}
+
+// Publish makes a draft live now
+func (s *Service) Publish(ctx context.Context, kind revision.Kind, id uint) (*DraftDto, error) {
+	draft, err := s.getDraft(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	published, err := s.repo.Publish(ctx, draft, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !published {
+		return nil, ErrAlreadyPublished
+	}
+	s.invalidate(ctx, kind.CacheTag())
+	result := toDto(draft)
+	return &result, nil
+}
+
+// Unpublish takes a published draft down and restores what was live before it
+func (s *Service) Unpublish(ctx context.Context, kind revision.Kind, id uint) (*DraftDto, error) {
+	draft, err := s.getDraft(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	unpublished, err := s.repo.Unpublish(ctx, draft, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !unpublished {
+		return nil, ErrNotPublished
+	}
+	s.invalidate(ctx, kind.CacheTag())
+	result := toDto(draft)
+	return &result, nil
+}
+
+// PreviewToken signs a token that lets the public frontend render the draft
+// before it is published
+func (s *Service) PreviewToken(ctx context.Context, kind revision.Kind, id uint) (*PreviewTokenDto, error) {
+	if _, err := s.getDraft(ctx, kind, id); err != nil {
+		return nil, err
+	}
+	token, expiresAt, err := s.jwt.GeneratePreviewToken(string(kind), id, s.previewTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &PreviewTokenDto{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// Preview returns the draft a preview token was issued for
+func (s *Service) Preview(ctx context.Context, token string) (*PreviewDto, error) {
+	claims, err := s.jwt.VerifyPreviewToken(token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	draft, err := s.getDraft(ctx, revision.Kind(claims.Kind), claims.DraftID)
+	if err != nil {
+		return nil, err
+	}
+	dto := toDto(draft)
+	return &PreviewDto{
+		Kind:     dto.Kind,
+		EntityID: dto.EntityID,
+		Content:  dto.Content,
+		Status:   dto.Status,
+	}, nil
+}
+
+// PublishDue publishes and unpublishes every draft whose scheduled time has
+// come. A draft that fails is logged and retried on the next run.
+func (s *Service) PublishDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	publish, unpublish, err := s.repo.GetDueDrafts(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range publish {
+		draft := &publish[i]
+		published, err := s.repo.Publish(ctx, draft, now)
+		if err != nil {
+			logger.Error("Failed to publish scheduled draft", "kind", draft.Kind, "draft", draft.ID, "error", err)
+			continue
+		}
+		if published {
+			logger.Info("Published scheduled draft", "kind", draft.Kind, "draft", draft.ID)
+			s.invalidate(ctx, revision.Kind(draft.Kind).CacheTag())
+		}
+	}
+	for i := range unpublish {
+		draft := &unpublish[i]
+		unpublished, err := s.repo.Unpublish(ctx, draft, now)
+		if err != nil {
+			logger.Error("Failed to unpublish scheduled draft", "kind", draft.Kind, "draft", draft.ID, "error", err)
+			continue
+		}
+		if unpublished {
+			logger.Info("Unpublished scheduled draft", "kind", draft.Kind, "draft", draft.ID)
+			s.invalidate(ctx, revision.Kind(draft.Kind).CacheTag())
+		}
+	}
+	return nil
+}
+
+// RunScheduler publishes due drafts now and then every interval until ctx is
+// cancelled
+func (s *Service) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PublishDue(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to run draft scheduler", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fill validates dto and copies it onto draft
+func (s *Service) fill(ctx context.Context, kind revision.Kind, draft *models.Draft, dto *SaveDraftDto) error {
+	sec, ok := sections[kind]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	if len(dto.Content) == 0 {
+		return fmt.Errorf("%w: content is required", ErrInvalidDraft)
+	}
+	if _, err := sec.decode(dto.Content); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDraft, err)
+	}
+	if dto.EntityID != nil {
+		if !sec.item() {
+			return fmt.Errorf("%w: %s is a page and takes no entity_id", ErrInvalidDraft, kind)
+		}
+		exists, err := s.repo.EntityExists(ctx, kind, *dto.EntityID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrEntityNotFound
+		}
+	}
+	if dto.PublishAt != nil && dto.UnpublishAt != nil && !dto.UnpublishAt.After(*dto.PublishAt) {
+		return fmt.Errorf("%w: unpublish_at must be after publish_at", ErrInvalidDraft)
+	}
+
+	draft.EntityID = dto.EntityID
+	draft.Payload = string(dto.Content)
+	draft.PublishAt = utc(dto.PublishAt)
+	draft.UnpublishAt = utc(dto.UnpublishAt)
+	return nil
+}
+
+func (s *Service) getDraft(ctx context.Context, kind revision.Kind, id uint) (*models.Draft, error) {
+	draft, err := s.repo.GetDraft(ctx, kind, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDraftNotFound
+	}
+	return draft, err
+}
+
+// utc stores schedule times in UTC so they compare correctly in SQLite,
+// which keeps them as text
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+func toDto(draft *models.Draft) DraftDto {
+	return DraftDto{
+		ID:            draft.ID,
+		Kind:          revision.Kind(draft.Kind),
+		EntityID:      draft.EntityID,
+		Content:       json.RawMessage(draft.Payload),
+		Status:        status(draft),
+		AuthorID:      draft.AuthorID,
+		PublishAt:     draft.PublishAt,
+		UnpublishAt:   draft.UnpublishAt,
+		PublishedAt:   draft.PublishedAt,
+		UnpublishedAt: draft.UnpublishedAt,
+		CreatedAt:     draft.CreatedAt,
+		UpdatedAt:     draft.UpdatedAt,
+	}
+}
+
+func status(draft *models.Draft) Status {
+	switch {
+	case draft.UnpublishedAt != nil:
+		return StatusUnpublished
+	case draft.PublishedAt != nil:
+		return StatusPublished
+	case draft.PublishAt != nil:
+		return StatusScheduled
+	default:
+		return StatusDraft
+	}
+}
@@ -2,9 +2,12 @@ package hero
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"gorm.io/gorm"
 )
 
 type Handler struct {
@@ -26,16 +29,26 @@ func (h *Handler) GetHeroPage(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(hero)
 }
 
+// UpdateHeroPage applies a JSON merge patch to the hero page
 func (h *Handler) UpdateHeroPage(w http.ResponseWriter, r *http.Request) {
-	var body HeroPageDto
+	body, err := h.service.Find(r.Context())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		body = &HeroPageDto{}
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// Decode the JSON request body
-	if err := utils.DecodeBody(r, &body); err != nil {
+	// Apply the JSON merge patch in the request body
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.Update(r.Context(), body); err != nil {
+	if err := h.service.Update(r.Context(), *body); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
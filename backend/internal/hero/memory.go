@@ -0,0 +1,48 @@
+package hero
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// MemoryHeroRepository is an in-memory implementation of HeroRepository for
+// tests and database-free runs. It is safe for concurrent use.
+type MemoryHeroRepository struct {
+	mu   sync.RWMutex
+	hero *HeroPageDto
+}
+
+// NewMemoryHeroRepository creates an empty MemoryHeroRepository
+func NewMemoryHeroRepository() *MemoryHeroRepository {
+	return &MemoryHeroRepository{}
+}
+
+// Find returns a copy of the hero page, or gorm.ErrRecordNotFound before the
+// first Update
+func (r *MemoryHeroRepository) Find(ctx context.Context) (*HeroPageDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.hero == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return cloneHero(r.hero), nil
+}
+
+// Update replaces the hero page, creating it if needed
+func (r *MemoryHeroRepository) Update(ctx context.Context, data *HeroPageDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hero = cloneHero(data)
+	return nil
+}
+
+func cloneHero(h *HeroPageDto) *HeroPageDto {
+	c := *h
+	c.Hobbies = slices.Clone(h.Hobbies)
+	return &c
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
 	"gorm.io/gorm"
 )
 
@@ -50,39 +52,44 @@ func (r *GormHeroRepository) Find(ctx context.Context) (*HeroPageDto, error) {
 
 // Update modifies the hero page
 func (r *GormHeroRepository) Update(ctx context.Context, data *HeroPageDto) error {
-	var existing models.HeroPage
-	err := r.db.WithContext(ctx).First(&existing).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			newHero := &models.HeroPage{
-				Name:        data.Name,
-				Rank:        data.Rank,
-				Title:       data.Title,
-				Subtitle:    data.Subtitle,
-				ResumeLink:  data.ResumeLink,
-				ContactLink: data.ContactLink,
-				ImageURL1:   data.ImageUrl1,
-				ImageURL2:   data.ImageUrl2,
-				ImageURL3:   data.ImageUrl3,
-				ImageURL4:   data.ImageUrl4,
-				Hobbies:     data.Hobbies,
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockPage(ctx, tx, &models.HeroPage{}); err != nil {
+			return err
+		}
+		var existing models.HeroPage
+		err := tx.First(&existing).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				newHero := &models.HeroPage{
+					Name:        data.Name,
+					Rank:        data.Rank,
+					Title:       data.Title,
+					Subtitle:    data.Subtitle,
+					ResumeLink:  data.ResumeLink,
+					ContactLink: data.ContactLink,
+					ImageURL1:   data.ImageUrl1,
+					ImageURL2:   data.ImageUrl2,
+					ImageURL3:   data.ImageUrl3,
+					ImageURL4:   data.ImageUrl4,
+					Hobbies:     data.Hobbies,
+				}
+				return revision.RecordWrite(tx.Create(newHero), revision.KindHero, models.RevisionCreate, newHero)
 			}
-			return r.db.WithContext(ctx).Create(newHero).Error
+			return err
 		}
-		return err
-	}
 
-	existing.Name = data.Name
-	existing.Rank = data.Rank
-	existing.Title = data.Title
-	existing.Subtitle = data.Subtitle
-	existing.ResumeLink = data.ResumeLink
-	existing.ContactLink = data.ContactLink
-	existing.ImageURL1 = data.ImageUrl1
-	existing.ImageURL2 = data.ImageUrl2
-	existing.ImageURL3 = data.ImageUrl3
-	existing.ImageURL4 = data.ImageUrl4
-	existing.Hobbies = data.Hobbies
+		existing.Name = data.Name
+		existing.Rank = data.Rank
+		existing.Title = data.Title
+		existing.Subtitle = data.Subtitle
+		existing.ResumeLink = data.ResumeLink
+		existing.ContactLink = data.ContactLink
+		existing.ImageURL1 = data.ImageUrl1
+		existing.ImageURL2 = data.ImageUrl2
+		existing.ImageURL3 = data.ImageUrl3
+		existing.ImageURL4 = data.ImageUrl4
+		existing.Hobbies = data.Hobbies
 
-	return r.db.WithContext(ctx).Save(&existing).Error
+		return revision.RecordWrite(tx.Save(&existing), revision.KindHero, models.RevisionUpdate, &existing)
+	})
 }
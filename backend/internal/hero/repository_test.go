@@ -0,0 +1,20 @@
+package hero_test
+
+import (
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/hero"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+)
+
+func TestMemoryHeroRepository(t *testing.T) {
+	repotest.RunHeroRepository(t, func(t *testing.T) hero.HeroRepository {
+		return hero.NewMemoryHeroRepository()
+	})
+}
+
+func TestGormHeroRepository(t *testing.T) {
+	repotest.RunHeroRepository(t, func(t *testing.T) hero.HeroRepository {
+		return hero.NewGormHeroRepository(repotest.NewSQLiteDB(t))
+	})
+}
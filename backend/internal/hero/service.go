@@ -2,15 +2,20 @@ package hero
 
 import (
 	"context"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
 )
 
 type Service struct {
-	repo HeroRepository
+	repo       HeroRepository
+	invalidate cache.Invalidator
 }
 
-func NewService(repo HeroRepository) *Service {
+// NewService calls invalidate after the hero page changed
+func NewService(repo HeroRepository, invalidate cache.Invalidator) *Service {
 	return &Service{
-		repo: repo,
+		repo:       repo,
+		invalidate: invalidate,
 	}
 }
 
@@ -20,5 +25,9 @@ func (s *Service) Find(ctx context.Context) (*HeroPageDto, error) {
 }
 
 func (s *Service) Update(ctx context.Context, data HeroPageDto) error {
-	return s.repo.Update(ctx, &data)
+	if err := s.repo.Update(ctx, &data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagHero)
+	return nil
 }
@@ -0,0 +1,54 @@
+package hero_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/hero"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+)
+
+// failingRepository fails every write
+type failingRepository struct {
+	hero.HeroRepository
+}
+
+func (failingRepository) Update(ctx context.Context, data *hero.HeroPageDto) error {
+	return errors.New("write failed")
+}
+
+func TestUpdateInvalidatesHero(t *testing.T) {
+	ctx := context.Background()
+	repo := hero.NewMemoryHeroRepository()
+	inv := &repotest.Invalidations{}
+	s := hero.NewService(repo, inv.Invalidate)
+
+	if err := s.Update(ctx, hero.HeroPageDto{Name: "Ada"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := s.Find(ctx)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if got.Name != "Ada" {
+		t.Errorf("Name = %q, want Ada", got.Name)
+	}
+	if want := (repotest.Invalidations{{cache.TagHero}}); !slices.EqualFunc(*inv, want, slices.Equal) {
+		t.Errorf("invalidated %v, want %v", *inv, want)
+	}
+}
+
+func TestFailedUpdateKeepsCache(t *testing.T) {
+	inv := &repotest.Invalidations{}
+	s := hero.NewService(failingRepository{hero.NewMemoryHeroRepository()}, inv.Invalidate)
+
+	if err := s.Update(context.Background(), hero.HeroPageDto{Name: "Ada"}); err == nil {
+		t.Fatal("Update succeeded on a failing repository")
+	}
+	if len(*inv) != 0 {
+		t.Errorf("invalidated %v after a failed write", *inv)
+	}
+}
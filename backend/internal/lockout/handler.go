@@ -0,0 +1,75 @@
+package lockout
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// GetLockouts handles GET /admin/lockouts
+func (h *Handler) GetLockouts(w http.ResponseWriter, r *http.Request) {
+	lockouts, err := h.store.ListLocked(r.Context())
+	if err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"length": len(lockouts),
+		"data":   lockouts,
+	})
+}
+
+// GetLockout handles GET /admin/lockouts/{email}
+func (h *Handler) GetLockout(w http.ResponseWriter, r *http.Request) {
+	email, ok := emailParam(w, r)
+	if !ok {
+		return
+	}
+	status, err := h.store.Status(r.Context(), email)
+	if err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, status)
+}
+
+// ClearLockout handles DELETE /admin/lockouts/{email}
+func (h *Handler) ClearLockout(w http.ResponseWriter, r *http.Request) {
+	email, ok := emailParam(w, r)
+	if !ok {
+		return
+	}
+	if err := h.store.Reset(r.Context(), email); err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+
+	actor := ""
+	if claims := middleware.GetUserFromContext(r.Context()); claims != nil {
+		actor = claims.Sub
+	}
+	logger.Info("Account lockout cleared", "email", NormalizeEmail(email), "by", actor)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func emailParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	email, err := url.PathUnescape(chi.URLParam(r, "email"))
+	if err != nil || NormalizeEmail(email) == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid email")
+		return "", false
+	}
+	return email, true
+}
@@ -0,0 +1,124 @@
+package lockout
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+// MemoryStore keeps the failure counters in the memory of this instance, for
+// deployments without Redis. A restart forgets them, so it only suits a
+// single instance. It is safe for concurrent use.
+type MemoryStore struct {
+	mu       sync.Mutex
+	policy   Policy
+	accounts *utils.ExpiringMap[*StatusDto] // by normalized email
+}
+
+func NewMemoryStore(policy Policy) *MemoryStore {
+	return &MemoryStore{
+		policy:   policy,
+		accounts: utils.NewExpiringMap[*StatusDto](),
+	}
+}
+
+func (s *MemoryStore) Check(ctx context.Context, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.accounts.Get(NormalizeEmail(email))
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if until := status.LockedUntil; until != nil && until.After(now) {
+		return &ThrottleError{RetryAfter: until.Sub(now), Locked: true}
+	}
+	if next := status.NextAttemptAt; next != nil && next.After(now) {
+		return &ThrottleError{RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// RecordFailure applies the same delays and lockout as the script of the
+// RedisStore
+func (s *MemoryStore) RecordFailure(ctx context.Context, email, ip string) error {
+	email = NormalizeEmail(email)
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.accounts.Get(email)
+	if !ok {
+		status = &StatusDto{Email: email}
+	}
+	status.Failures++
+	status.LastFailureAt = &now
+	status.LastIP = ip
+
+	if status.Failures >= s.policy.MaxFailures {
+		lockedUntil := now.Add(s.policy.LockoutDuration)
+		status.LockedUntil = &lockedUntil
+		status.NextAttemptAt = nil
+		s.accounts.Set(email, status, s.policy.LockoutDuration)
+		logLocked(email, ip, int64(status.Failures), lockedUntil)
+		return nil
+	}
+
+	if status.Failures > freeAttempts {
+		delay := min(time.Duration(float64(baseDelay)*math.Pow(2, float64(status.Failures-freeAttempts-1))), maxDelay)
+		next := now.Add(delay)
+		status.NextAttemptAt = &next
+	}
+	s.accounts.Set(email, status, s.policy.Window)
+	return nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts.Delete(NormalizeEmail(email))
+	return nil
+}
+
+func (s *MemoryStore) Status(ctx context.Context, email string) (*StatusDto, error) {
+	email = NormalizeEmail(email)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.accounts.Get(email)
+	if !ok {
+		return &StatusDto{Email: email}, nil
+	}
+	status := *stored
+	status.Locked = status.LockedUntil != nil && status.LockedUntil.After(time.Now())
+	return &status, nil
+}
+
+func (s *MemoryStore) ListLocked(ctx context.Context) ([]StatusDto, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	lockouts := []StatusDto{}
+	s.accounts.Range(func(email string, stored *StatusDto) {
+		if stored.LockedUntil != nil && stored.LockedUntil.After(now) {
+			status := *stored
+			status.Locked = true
+			lockouts = append(lockouts, status)
+		}
+	})
+	sort.Slice(lockouts, func(i, j int) bool {
+		if !lockouts[i].LockedUntil.Equal(*lockouts[j].LockedUntil) {
+			return lockouts[i].LockedUntil.Before(*lockouts[j].LockedUntil)
+		}
+		return lockouts[i].Email < lockouts[j].Email
+	})
+	return lockouts, nil
+}
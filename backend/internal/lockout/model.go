@@ -0,0 +1,18 @@
+package lockout
+
+import "time"
+
+// StatusDto describes the failed-login state of a single account
+type StatusDto struct {
+	Email         string     `json:"email"`
+	Failures      int        `json:"failures"`
+	LastFailureAt *time.Time `json:"last_failure_at"`
+	LastIP        string     `json:"last_ip"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	Locked        bool       `json:"locked"`
+}
+
+type StatusListDto struct {
+	Lockouts []StatusDto `json:"lockouts"`
+}
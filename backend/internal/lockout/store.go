@@ -0,0 +1,231 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// freeAttempts failures are allowed before delays kick in
+	freeAttempts = 3
+	// baseDelay doubles with every failure past freeAttempts, up to maxDelay
+	baseDelay = time.Second
+	maxDelay  = 30 * time.Second
+
+	lockedIndexKey = "login_lockouts"
+)
+
+// ThrottleError is returned when an account must wait before trying again,
+// either because of a progressive delay or a temporary lockout.
+type ThrottleError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *ThrottleError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("account locked, retry in %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("too many failed attempts, retry in %s", e.RetryAfter)
+}
+
+// Policy controls when an account gets locked
+type Policy struct {
+	// MaxFailures within Window lock the account for LockoutDuration
+	MaxFailures     int
+	Window          time.Duration
+	LockoutDuration time.Duration
+}
+
+// Store tracks failed logins per account. Accounts are keyed by normalized
+// email, whether or not the account exists, so responses do not reveal which
+// emails are registered.
+type Store interface {
+	// Check returns a *ThrottleError while the account is delayed or locked
+	Check(ctx context.Context, email string) error
+	// RecordFailure counts a failed attempt. Repeated failures delay the
+	// next allowed attempt and eventually lock the account.
+	RecordFailure(ctx context.Context, email, ip string) error
+	// Reset clears the failure count after a successful login
+	Reset(ctx context.Context, email string) error
+	// Status returns the failure state of an account
+	Status(ctx context.Context, email string) (*StatusDto, error)
+	// ListLocked returns every account that is currently locked, soonest
+	// to unlock first
+	ListLocked(ctx context.Context) ([]StatusDto, error)
+}
+
+// RedisStore keeps the failure counters in Redis, so they survive restarts
+// and are shared between instances
+type RedisStore struct {
+	client *redis.Client
+	policy Policy
+}
+
+func NewRedisStore(client *redis.Client, policy Policy) *RedisStore {
+	return &RedisStore{
+		client: client,
+		policy: policy,
+	}
+}
+
+// NormalizeEmail is the account key used for tracking
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func failuresKey(email string) string {
+	return "login_failures:" + email
+}
+
+func (s *RedisStore) Check(ctx context.Context, email string) error {
+	email = NormalizeEmail(email)
+	vals, err := s.client.HMGet(ctx, failuresKey(email), "locked_until", "next_attempt").Result()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if until := parseUnixMilli(vals[0]); until != nil && until.After(now) {
+		return &ThrottleError{RetryAfter: until.Sub(now), Locked: true}
+	}
+	if next := parseUnixMilli(vals[1]); next != nil && next.After(now) {
+		return &ThrottleError{RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// failureScript counts a failure and applies the delay or lockout.
+// KEYS: failures hash, locked index
+// ARGV (milliseconds where timed): now, window, free attempts, max failures, lockout, email, ip,
+// base delay, max delay
+// Returns {failures, locked_until}; locked_until is 0 unless just locked.
+var failureScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local failures = redis.call("HINCRBY", KEYS[1], "failures", 1)
+redis.call("HSET", KEYS[1], "last_failure", now, "last_ip", ARGV[7])
+
+if failures >= tonumber(ARGV[4]) then
+	local locked_until = now + tonumber(ARGV[5])
+	redis.call("HSET", KEYS[1], "locked_until", locked_until)
+	redis.call("HDEL", KEYS[1], "next_attempt")
+	redis.call("PEXPIRE", KEYS[1], ARGV[5])
+	redis.call("ZADD", KEYS[2], locked_until, ARGV[6])
+	return {failures, locked_until}
+end
+
+local free = tonumber(ARGV[3])
+if failures > free then
+	local delay = math.min(tonumber(ARGV[8]) * 2 ^ (failures - free - 1), tonumber(ARGV[9]))
+	redis.call("HSET", KEYS[1], "next_attempt", now + delay)
+end
+redis.call("PEXPIRE", KEYS[1], ARGV[2])
+return {failures, 0}
+`)
+
+func (s *RedisStore) RecordFailure(ctx context.Context, email, ip string) error {
+	email = NormalizeEmail(email)
+	now := time.Now()
+	res, err := failureScript.Run(ctx, s.client,
+		[]string{failuresKey(email), lockedIndexKey},
+		now.UnixMilli(),
+		s.policy.Window.Milliseconds(),
+		freeAttempts,
+		s.policy.MaxFailures,
+		s.policy.LockoutDuration.Milliseconds(),
+		email,
+		ip,
+		baseDelay.Milliseconds(),
+		maxDelay.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return err
+	}
+
+	if res[1] > 0 {
+		logLocked(email, ip, res[0], time.UnixMilli(res[1]))
+	}
+	return nil
+}
+
+func logLocked(email, ip string, failures int64, lockedUntil time.Time) {
+	logger.Warn("Account locked after failed logins",
+		"email", email,
+		"ip", ip,
+		"failures", failures,
+		"locked_until", lockedUntil.UTC(),
+	)
+}
+
+func (s *RedisStore) Reset(ctx context.Context, email string) error {
+	email = NormalizeEmail(email)
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, failuresKey(email))
+	pipe.ZRem(ctx, lockedIndexKey, email)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisStore) Status(ctx context.Context, email string) (*StatusDto, error) {
+	email = NormalizeEmail(email)
+	vals, err := s.client.HGetAll(ctx, failuresKey(email)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &StatusDto{
+		Email:         email,
+		LastIP:        vals["last_ip"],
+		LastFailureAt: parseUnixMilli(vals["last_failure"]),
+		NextAttemptAt: parseUnixMilli(vals["next_attempt"]),
+		LockedUntil:   parseUnixMilli(vals["locked_until"]),
+	}
+	status.Failures, _ = strconv.Atoi(vals["failures"])
+	status.Locked = status.LockedUntil != nil && status.LockedUntil.After(time.Now())
+	return status, nil
+}
+
+func (s *RedisStore) ListLocked(ctx context.Context) ([]StatusDto, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	// Expired lockouts are dropped from the index lazily
+	if err := s.client.ZRemRangeByScore(ctx, lockedIndexKey, "-inf", now).Err(); err != nil {
+		return nil, err
+	}
+	emails, err := s.client.ZRange(ctx, lockedIndexKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	lockouts := []StatusDto{}
+	for _, email := range emails {
+		status, err := s.Status(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if status.Locked {
+			lockouts = append(lockouts, *status)
+		}
+	}
+	return lockouts, nil
+}
+
+// parseUnixMilli reads a millisecond timestamp stored in Redis
+func parseUnixMilli(v interface{}) *time.Time {
+	str, ok := v.(string)
+	if !ok || str == "" {
+		return nil
+	}
+	ms, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return nil
+	}
+	t := time.UnixMilli(ms).UTC()
+	return &t
+}
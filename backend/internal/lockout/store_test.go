@@ -0,0 +1,111 @@
+package lockout_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/othersidedrl/portfolio/backend/internal/lockout"
+	"github.com/redis/go-redis/v9"
+)
+
+var policy = lockout.Policy{MaxFailures: 5, Window: time.Hour, LockoutDuration: time.Hour}
+
+// forEachStore runs test against a store in Redis, then one in memory
+func forEachStore(t *testing.T, test func(t *testing.T, s lockout.Store)) {
+	t.Run("redis", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		test(t, lockout.NewRedisStore(client, policy))
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, lockout.NewMemoryStore(policy))
+	})
+}
+
+func mustStatus(t *testing.T, s lockout.Store, email string) *lockout.StatusDto {
+	t.Helper()
+	status, err := s.Status(context.Background(), email)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	return status
+}
+
+func TestFailuresDelayThenLock(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s lockout.Store) {
+		ctx := context.Background()
+
+		for i := 1; i <= 3; i++ {
+			if err := s.RecordFailure(ctx, "owner@example.com", "10.0.0.1"); err != nil {
+				t.Fatalf("RecordFailure: %v", err)
+			}
+			if err := s.Check(ctx, "owner@example.com"); err != nil {
+				t.Fatalf("Check after %d failures = %v, want the attempt allowed", i, err)
+			}
+		}
+
+		// Past the free attempts every failure delays the next attempt
+		s.RecordFailure(ctx, "owner@example.com", "10.0.0.1")
+		var throttle *lockout.ThrottleError
+		err := s.Check(ctx, "owner@example.com")
+		if !errors.As(err, &throttle) || throttle.Locked || throttle.RetryAfter <= 0 || throttle.RetryAfter > time.Second {
+			t.Fatalf("Check after 4 failures = %v, want a delay of up to a second", err)
+		}
+
+		// Accounts are keyed by normalized email
+		s.RecordFailure(ctx, " Owner@Example.com ", "10.0.0.2")
+		err = s.Check(ctx, "OWNER@example.com")
+		if !errors.As(err, &throttle) || !throttle.Locked || throttle.RetryAfter <= 59*time.Minute {
+			t.Fatalf("Check after 5 failures = %v, want the account locked for an hour", err)
+		}
+
+		status := mustStatus(t, s, "owner@example.com")
+		if status.Failures != 5 || !status.Locked || status.LastIP != "10.0.0.2" || status.LockedUntil == nil || status.NextAttemptAt != nil {
+			t.Errorf("status = %+v, want 5 failures, locked, last from 10.0.0.2", status)
+		}
+		if err := s.Check(ctx, "other@example.com"); err != nil {
+			t.Errorf("Check of another account = %v, want it allowed", err)
+		}
+	})
+}
+
+func TestListLockedAndReset(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s lockout.Store) {
+		ctx := context.Background()
+
+		for _, email := range []string{"first@example.com", "second@example.com"} {
+			for range policy.MaxFailures {
+				s.RecordFailure(ctx, email, "10.0.0.1")
+			}
+		}
+		s.RecordFailure(ctx, "delayed@example.com", "10.0.0.1")
+
+		locked, err := s.ListLocked(ctx)
+		if err != nil {
+			t.Fatalf("ListLocked: %v", err)
+		}
+		if len(locked) != 2 || locked[0].Email != "first@example.com" || locked[1].Email != "second@example.com" {
+			t.Fatalf("locked = %+v, want first then second", locked)
+		}
+
+		if err := s.Reset(ctx, "first@example.com"); err != nil {
+			t.Fatalf("Reset: %v", err)
+		}
+		if err := s.Check(ctx, "first@example.com"); err != nil {
+			t.Errorf("Check after Reset = %v, want the attempt allowed", err)
+		}
+		if status := mustStatus(t, s, "first@example.com"); status.Failures != 0 || status.Locked {
+			t.Errorf("status after Reset = %+v, want no failures", status)
+		}
+		locked, err = s.ListLocked(ctx)
+		if err != nil {
+			t.Fatalf("ListLocked: %v", err)
+		}
+		if len(locked) != 1 || locked[0].Email != "second@example.com" {
+			t.Errorf("locked after Reset = %+v, want only second", locked)
+		}
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"os"
 )
 
-var Log *slog.Logger
+// Log falls back to slog's default logger until Init is called, so packages
+// used from tests can log without setting it up
+var Log = slog.Default()
 
 func Init() {
 	opts := &slog.HandlerOptions{
@@ -3,8 +3,11 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
 )
 
@@ -12,21 +15,83 @@ type contextKey string
 
 const userContextKey = contextKey("user")
 
-// AuthGuard checks for a valid JWT in the Authorization header
-func AuthGuard(jwt *utils.JWTService) func(http.Handler) http.Handler {
+// TokenDenylist reports whether an access token has been revoked
+type TokenDenylist interface {
+	IsRevoked(ctx context.Context, jti, family string) (bool, error)
+}
+
+// APIKeyVerifier resolves an API key to the principal it acts as
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, raw string) (*utils.JWTClaims, error)
+}
+
+// SessionValidator rejects tokens whose session has been signed out
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, id string) error
+}
+
+// AuthGuardConfig wires the credential checks used by AuthGuard
+type AuthGuardConfig struct {
+	JWT      *utils.JWTService
+	Denylist TokenDenylist
+	// Sessions is optional; when set, access tokens must belong to an active session
+	Sessions SessionValidator
+	// APIKeys is optional; when nil, API keys are rejected
+	APIKeys APIKeyVerifier
+	// Cookies accepts the access token from the access_token cookie when no
+	// Authorization header is sent. Pair it with CSRFProtect.
+	Cookies bool
+}
+
+// AuthGuard checks for a valid, non-revoked JWT or API key in the Authorization
+// header, or a JWT in the session cookie when enabled
+func AuthGuard(cfg AuthGuardConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+			token, fromCookie := bearerToken(r, cfg.Cookies)
+			if token == "" {
 				http.Error(w, "Unauthorized: missing or malformed token", http.StatusUnauthorized)
 				return
 			}
 
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			claims, err := jwt.VerifyToken(token)
-			if err != nil {
-				http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
-				return
+			var claims *utils.JWTClaims
+			if !fromCookie && strings.HasPrefix(token, models.APIKeyTokenPrefix) {
+				if cfg.APIKeys == nil {
+					http.Error(w, "Unauthorized: API keys are not accepted here", http.StatusUnauthorized)
+					return
+				}
+
+				var err error
+				claims, err = cfg.APIKeys.VerifyAPIKey(r.Context(), token)
+				if err != nil {
+					http.Error(w, "Unauthorized: invalid API key", http.StatusUnauthorized)
+					return
+				}
+			} else {
+				var err error
+				claims, err = cfg.JWT.VerifyToken(token)
+				if err != nil {
+					http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+					return
+				}
+
+				revoked, err := cfg.Denylist.IsRevoked(r.Context(), claims.ID, claims.Family)
+				if err != nil {
+					logger.Error("Failed to check token revocation", "error", err)
+					http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				if revoked {
+					http.Error(w, "Unauthorized: token revoked", http.StatusUnauthorized)
+					return
+				}
+
+				if cfg.Sessions != nil {
+					if err := cfg.Sessions.ValidateSession(r.Context(), claims.Family); err != nil {
+						http.Error(w, "Unauthorized: session revoked", http.StatusUnauthorized)
+						return
+					}
+				}
 			}
 
 			// Store claims in context so handlers can access it
@@ -36,6 +101,55 @@ func AuthGuard(jwt *utils.JWTService) func(http.Handler) http.Handler {
 	}
 }
 
+// bearerToken returns the credential from the Authorization header, falling
+// back to the access_token cookie when allowed
+func bearerToken(r *http.Request, allowCookie bool) (token string, fromCookie bool) {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return "", false
+		}
+		return strings.TrimPrefix(authHeader, "Bearer "), false
+	}
+	if allowCookie {
+		if cookie, err := r.Cookie(AccessTokenCookie); err == nil {
+			return cookie.Value, true
+		}
+	}
+	return "", false
+}
+
+// RequireScope only lets through principals holding at least one of the given
+// scopes. Users hold the scopes of their role; API keys hold the scopes they
+// were created with. It must be mounted after AuthGuard.
+func RequireScope(scopes ...models.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetUserFromContext(r.Context())
+			if claims == nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if !slices.ContainsFunc(scopes, func(scope models.Scope) bool { return hasScope(claims, scope) }) {
+				http.Error(w, "Forbidden: insufficient scope", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func hasScope(claims *utils.JWTClaims, scope models.Scope) bool {
+	if !models.UserRole(claims.Role).HasScope(scope) {
+		return false
+	}
+	if claims.APIKeyID != 0 {
+		return slices.Contains(claims.Scopes, string(scope))
+	}
+	return true
+}
+
 // GetUserFromContext retrieves the JWT claims from the request context
 func GetUserFromContext(ctx context.Context) *utils.JWTClaims {
 	claims, ok := ctx.Value(userContextKey).(*utils.JWTClaims)
@@ -1,114 +1,449 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"golang.org/x/sync/singleflight"
 )
 
-func RedisCache(client *redis.Client, key string, ttl time.Duration, handler http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-
-		// Try to get cached response
-		cached, err := client.Get(ctx, key).Result()
-		if err == nil {
-			// Cache hit
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(cached))
-			return
+const (
+	// validatorTTL is how long the validators of a response are kept after
+	// its entry expires, so a body that is cached again unchanged keeps its
+	// Last-Modified
+	validatorTTL = 24 * time.Hour
+	// renderTimeout bounds a render shared by several requests or run in
+	// the background, and is how long the instance rendering a response
+	// holds its lock
+	renderTimeout = 10 * time.Second
+	// A request that finds another instance rendering its response polls
+	// for the result every lockPollInterval, for up to lockWait, before
+	// rendering it itself
+	lockPollInterval = 50 * time.Millisecond
+	lockWait         = 2 * time.Second
+)
+
+var (
+	// renders coalesces the requests of this instance that miss the same
+	// key into one handler call
+	renders singleflight.Group
+	// refreshing holds the keys this instance is refreshing in the
+	// background
+	refreshing sync.Map
+)
+
+// CacheTTL says how long cached responses are used. A response is served as
+// is for Fresh, then for Stale more while one request refreshes it in the
+// background; after that it is rendered again before answering. A 404 is
+// cached for Negative, and not at all if that is 0.
+type CacheTTL struct {
+	Fresh    time.Duration
+	Stale    time.Duration
+	Negative time.Duration
+}
+
+// HTTPCaching is what browsers and CDNs are told about cached public
+// responses: they may reuse one for MaxAge, and for StaleWhileRevalidate
+// after that while they revalidate it in the background
+type HTTPCaching struct {
+	MaxAge               time.Duration
+	StaleWhileRevalidate time.Duration
+}
+
+func (c HTTPCaching) header() string {
+	return fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d",
+		int(c.MaxAge.Seconds()), int(c.StaleWhileRevalidate.Seconds()))
+}
+
+// validator identifies a version of a cached response for conditional GETs
+type validator struct {
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// cacheEntry is a cached response: a 200 with its validators, or a 404
+type cacheEntry struct {
+	validator
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        string    `json:"body"`
+	FreshUntil  time.Time `json:"fresh_until"`
+}
+
+// CacheKey derives the cache key of a request from the parts of it that
+// select a different response, so nothing else can fragment the cache or be
+// served a body meant for another request
+type CacheKey struct {
+	// Query allow-lists the query parameters the handler reads. Others, like
+	// tracking parameters, are left out of the key.
+	Query []string
+	// Headers lists the request headers the handler negotiates on, e.g.
+	// Accept-Language. They are part of the key and sent back in Vary.
+	Headers []string
+}
+
+// Build returns the key of r under baseKey, e.g.
+// "project_items_cache:GET?page=2&sort=name|accept-language=en". Allowed
+// query parameters are sorted by name and header values are normalized, so
+// equivalent requests share a key.
+func (k CacheKey) Build(baseKey string, r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(baseKey)
+	b.WriteString(":")
+	b.WriteString(r.Method)
+
+	query := r.URL.Query()
+	allowed := url.Values{}
+	for _, name := range k.Query {
+		if values, ok := query[name]; ok {
+			allowed[name] = values
 		}
+	}
+	if len(allowed) > 0 {
+		// Encode sorts by name
+		b.WriteString("?")
+		b.WriteString(allowed.Encode())
+	}
+
+	for _, name := range k.Headers {
+		b.WriteString("|")
+		b.WriteString(strings.ToLower(name))
+		b.WriteString("=")
+		b.WriteString(normalizeHeader(r.Header.Values(name)))
+	}
+	return b.String()
+}
 
-		// Cache miss: capture response
-		rec := NewResponseRecorder(w)
-		handler(rec, r)
+// Vary adds the headers the key depends on to the Vary header of w
+func (k CacheKey) Vary(w http.ResponseWriter) {
+	for _, name := range k.Headers {
+		w.Header().Add("Vary", http.CanonicalHeaderKey(name))
+	}
+}
 
-		// Only cache successful responses
-		if rec.StatusCode == http.StatusOK {
-			client.Set(ctx, key, rec.Body.String(), ttl)
+// normalizeHeader joins the values of a list header, like
+// "en-US, en;q=0.9", lower-cased and without spaces
+func normalizeHeader(values []string) string {
+	var parts []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.ToLower(strings.ReplaceAll(part, " ", "")); part != "" {
+				parts = append(parts, part)
+			}
 		}
 	}
+	return url.QueryEscape(strings.Join(parts, ","))
 }
 
-// RedisCacheWithParams creates cache keys dynamically based on request method and query parameters
-func RedisCacheWithParams(client *redis.Client, baseKey string, ttl time.Duration, handler http.HandlerFunc) http.HandlerFunc {
+// Cache caches the responses of handler in store under key for ttl,
+// registered under tags so a write to the content they show drops them.
+// Requests that miss at the same time share one handler call, on this
+// instance and, through a lock in store, across instances. Responses carry an
+// ETag and Last-Modified, and a request whose If-None-Match or
+// If-Modified-Since matches is answered with 304.
+func Cache(store cache.Store, key string, tags []cache.Tag, ttl CacheTTL, public HTTPCaching, handler http.HandlerFunc) http.HandlerFunc {
+	route := newCachedRoute(store, tags, ttl, public, handler)
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+		route.serve(w, r, key)
+	}
+}
 
+// CacheWithParams caches each variant of a response under baseKey, with the
+// key derived from the request by key. Every variant is registered under tags
+// and served like in Cache.
+func CacheWithParams(store cache.Store, baseKey string, key CacheKey, tags []cache.Tag, ttl CacheTTL, public HTTPCaching, handler http.HandlerFunc) http.HandlerFunc {
+	route := newCachedRoute(store, tags, ttl, public, handler)
+	return func(w http.ResponseWriter, r *http.Request) {
 		// Generate dynamic cache key
-		cacheKey := fmt.Sprintf("%s:%s", baseKey, r.Method)
+		cacheKey := key.Build(baseKey, r)
+		key.Vary(w)
+
+		route.serve(w, r, cacheKey)
+	}
+}
+
+// cachedRoute serves the cached responses of one handler
+type cachedRoute struct {
+	store   cache.Store
+	tags    []cache.Tag
+	ttl     CacheTTL
+	public  HTTPCaching
+	handler http.HandlerFunc
+}
+
+func newCachedRoute(store cache.Store, tags []cache.Tag, ttl CacheTTL, public HTTPCaching, handler http.HandlerFunc) *cachedRoute {
+	return &cachedRoute{
+		store:   store,
+		tags:    tags,
+		ttl:     ttl,
+		public:  public,
+		handler: handler,
+	}
+}
 
-		// Add query parameters to cache key
-		category := r.URL.Query().Get("category")
-		if category != "" {
-			cacheKey += fmt.Sprintf(":category=%s", category)
+func (c *cachedRoute) serve(w http.ResponseWriter, r *http.Request, key string) {
+	entry, ok := c.load(r.Context(), key)
+	switch {
+	case ok && time.Now().Before(entry.FreshUntil):
+		// Cache hit
+	case ok && entry.Status == http.StatusOK:
+		// Stale: answer with it while one request renders it again
+		c.refreshInBackground(r, key)
+	default:
+		entry = c.render(r, key)
+	}
+	writeEntry(w, r, entry, c.public)
+}
+
+// load returns the cached entry of key. Entries cached before they had a
+// status are treated as a miss.
+func (c *cachedRoute) load(ctx context.Context, key string) (*cacheEntry, bool) {
+	cached, err := c.store.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if json.Unmarshal(cached, &entry) != nil {
+		return nil, false
+	}
+	switch entry.Status {
+	case http.StatusOK:
+		return &entry, entry.ETag != ""
+	case http.StatusNotFound:
+		return &entry, true
+	}
+	return nil, false
+}
+
+// render renders key once for every request of this instance that misses
+// it. The render is not cut short when the request that started it goes
+// away, since the others are waiting for it too.
+func (c *cachedRoute) render(r *http.Request, key string) *cacheEntry {
+	result, _, _ := renders.Do(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), renderTimeout)
+		defer cancel()
+
+		unlock, locked := c.lock(ctx, key)
+		if !locked {
+			// Another instance is rendering it
+			if entry, ok := c.waitFor(ctx, key); ok {
+				return entry, nil
+			}
+			return c.run(r.Clone(ctx), key), nil
 		}
+		defer unlock()
 
-		// Try to get cached response
-		cached, err := client.Get(ctx, cacheKey).Result()
-		if err == nil {
-			// Cache hit
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(cached))
-			return
+		// It may have been stored while this instance waited for the lock
+		if entry, ok := c.load(ctx, key); ok && time.Now().Before(entry.FreshUntil) {
+			return entry, nil
 		}
+		return c.run(r.Clone(ctx), key), nil
+	})
+	return result.(*cacheEntry)
+}
 
-		// Cache miss: capture response
-		rec := NewResponseRecorder(w)
-		handler(rec, r)
+// refreshInBackground renders a stale key again after the request that found
+// it was answered, unless this or another instance is refreshing it already
+func (c *cachedRoute) refreshInBackground(r *http.Request, key string) {
+	if _, busy := refreshing.LoadOrStore(key, struct{}{}); busy {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), renderTimeout)
+	r = r.Clone(ctx)
+
+	go func() {
+		defer refreshing.Delete(key)
+		defer cancel()
 
-		// Only cache successful responses
-		if rec.StatusCode == http.StatusOK {
-			client.Set(ctx, cacheKey, rec.Body.String(), ttl)
+		unlock, locked := c.lock(ctx, key)
+		if !locked {
+			return
 		}
+		defer unlock()
+		c.run(r, key)
+	}()
+}
+
+// lock takes the right to render key across instances. It only reports
+// false when another instance holds it; if the store fails, the caller
+// renders without the lock.
+func (c *cachedRoute) lock(ctx context.Context, key string) (unlock func(), locked bool) {
+	unlock, err := c.store.Lock(ctx, key+":lock", renderTimeout)
+	if errors.Is(err, cache.ErrLocked) {
+		return nil, false
+	}
+	if err != nil {
+		logger.Warn("Failed to lock cache key", "key", key, "error", err)
+		return func() {}, true
 	}
+	return unlock, true
 }
 
-func RemoveCache(client *redis.Client, key string, handler http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		rec := NewResponseRecorder(w)
-		handler(rec, r)
-
-		// Only refresh if success
-		if rec.StatusCode >= 200 && rec.StatusCode < 300 {
-			// Refresh the cache using the captured body
-			err := client.Del(r.Context(), key).Err()
-			if err != nil {
-				fmt.Println("⚠️ Failed to refresh cache:", err)
-			} else {
-				fmt.Println("✅ Refreshed cache key:", key)
-			}
+// waitFor polls for the entry another instance is rendering
+func (c *cachedRoute) waitFor(ctx context.Context, key string) (*cacheEntry, bool) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(lockWait)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-deadline:
+			return nil, false
+		case <-ticker.C:
+		}
+		if entry, ok := c.load(ctx, key); ok && time.Now().Before(entry.FreshUntil) {
+			return entry, true
 		}
 	}
 }
 
-// RemoveCacheWithParams invalidates cache for endpoints with query parameters
-func RemoveCacheWithParams(client *redis.Client, baseKey string, handler http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		rec := NewResponseRecorder(w)
-		handler(rec, r)
-
-		// Only refresh if success
-		if rec.StatusCode >= 200 && rec.StatusCode < 300 {
-			// Use pattern matching to delete all related cache keys
-			pattern := fmt.Sprintf("%s:*", baseKey)
-			iter := client.Scan(r.Context(), 0, pattern, 0).Iterator()
-
-			deletedCount := 0
-			for iter.Next(r.Context()) {
-				err := client.Del(r.Context(), iter.Val()).Err()
-				if err == nil {
-					deletedCount++
-				}
-			}
+// run calls the handler and caches a 200 or 404. The key is registered
+// under its tags first, and the entry is not stored if a write invalidated
+// them while the handler ran, since it may show the content from before.
+func (c *cachedRoute) run(r *http.Request, key string) *cacheEntry {
+	ctx := r.Context()
+	registered := true
+	if err := c.store.Register(ctx, key, c.tags...); err != nil {
+		logger.Warn("Failed to register cache tags", "key", key, "error", err)
+		registered = false
+	}
 
-			if iter.Err() != nil {
-				fmt.Println("⚠️ Failed to refresh cache:", iter.Err())
-			} else {
-				fmt.Printf("✅ Refreshed %d cache keys matching: %s\n", deletedCount, pattern)
-			}
+	buf := newBufferedResponse()
+	c.handler(buf, r)
+
+	now := time.Now()
+	entry := &cacheEntry{
+		Status:      buf.status,
+		ContentType: buf.header.Get("Content-Type"),
+		Body:        buf.body.String(),
+	}
+	var ttl time.Duration
+	switch buf.status {
+	case http.StatusOK:
+		entry.validator = c.validatorFor(ctx, key, buf.body.Bytes(), now)
+		entry.FreshUntil = now.Add(c.ttl.Fresh)
+		ttl = c.ttl.Fresh + c.ttl.Stale
+	case http.StatusNotFound:
+		entry.FreshUntil = now.Add(c.ttl.Negative)
+		ttl = c.ttl.Negative
+	}
+	if !registered || ttl <= 0 {
+		return entry
+	}
+
+	if current, err := c.store.Registered(ctx, key, c.tags...); err != nil || !current {
+		return entry
+	}
+	if data, err := json.Marshal(entry); err == nil {
+		c.store.Set(ctx, key, data, ttl)
+	}
+	return entry
+}
+
+// validatorFor returns the validators of body and stores them. A body that
+// is unchanged since it was last cached keeps its Last-Modified.
+func (c *cachedRoute) validatorFor(ctx context.Context, key string, body []byte, now time.Time) validator {
+	v := validator{
+		ETag:         bodyETag(body),
+		LastModified: now.UTC().Truncate(time.Second),
+	}
+	validatorKey := key + ":validator"
+	if cached, err := c.store.Get(ctx, validatorKey); err == nil {
+		var prev validator
+		if json.Unmarshal(cached, &prev) == nil && prev.ETag == v.ETag {
+			v.LastModified = prev.LastModified
 		}
 	}
+	if data, err := json.Marshal(v); err == nil {
+		c.store.Set(ctx, validatorKey, data, validatorTTL)
+	}
+	return v
+}
+
+// writeEntry sends a cached response, or 304 if the client has it already
+func writeEntry(w http.ResponseWriter, r *http.Request, entry *cacheEntry, public HTTPCaching) {
+	h := w.Header()
+	if entry.ContentType != "" {
+		h.Set("Content-Type", entry.ContentType)
+	} else {
+		h.Set("Content-Type", "application/json")
+	}
+	if entry.Status != http.StatusOK {
+		w.WriteHeader(entry.Status)
+		w.Write([]byte(entry.Body))
+		return
+	}
+
+	h.Set("ETag", entry.ETag)
+	h.Set("Last-Modified", entry.LastModified.UTC().Format(http.TimeFormat))
+	h.Set("Cache-Control", public.header())
+
+	if notModified(r, entry.validator) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Write([]byte(entry.Body))
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when there is
+// none, against the current validators of a response
+func notModified(r *http.Request, v validator) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return matchesWeak(ifNoneMatch, v.ETag)
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !v.LastModified.After(since)
+}
+
+// bodyETag is a weak entity tag, since responses are compressed on the way
+// out and so are only semantically equivalent to the hashed body
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "W/" + ETag(hex.EncodeToString(sum[:16]))
+}
+
+// bufferedResponse keeps what a handler writes instead of sending it, so it
+// can be cached and sent to every request that shares the render
+type bufferedResponse struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if !b.wroteHeader {
+		b.status = code
+		b.wroteHeader = true
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
 }
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+// ErrPreconditionFailed is returned by a write that found its row changed
+// after IfMatch checked it, by a request that raced this one
+var ErrPreconditionFailed = errors.New("resource changed since it was read")
+
+const preconditionContextKey = contextKey("precondition")
+
+// precondition is what IfMatch leaves in the context of a request it let
+// through: the version it checked, and how to answer if the write finds the
+// row changed since
+type precondition struct {
+	version string
+	failed  http.HandlerFunc
+	claimed atomic.Bool
+}
+
+// VersionFunc reports an opaque version of the resource addressed by r that
+// changes whenever the resource does. found is false when it does not exist.
+type VersionFunc func(r *http.Request) (version string, found bool, err error)
+
+// ETag quotes a version as a strong entity tag
+func ETag(version string) string {
+	return `"` + version + `"`
+}
+
+// WithETag tags the response of handler with the current version of the
+// resource, so a client can send it back in If-Match when it edits it
+func WithETag(version VersionFunc, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Read before the handler so a concurrent edit can only make the tag
+		// older than the body, which fails safe with a 412 later
+		if v, found, err := version(r); err == nil && found {
+			w.Header().Set("ETag", ETag(v))
+		}
+		handler(w, r)
+	}
+}
+
+// IfMatch guards a write, like a PATCH, DELETE or a publish, with optimistic
+// concurrency control. The request must carry an If-Match header with the
+// version the client last read; if the resource changed since, the request
+// fails with 412 and the current representation, written by current,
+// together with its ETag. A successful write is tagged with the new version.
+//
+// The check alone cannot stop a write racing in right after it, so the
+// version checked is passed on in the context: repositories re-check it in
+// their write transaction, see ClaimMatchedVersion, and handlers answer the
+// resulting ErrPreconditionFailed with WritePreconditionFailed.
+func IfMatch(version VersionFunc, current http.HandlerFunc, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ifMatch := r.Header.Get("If-Match")
+		if ifMatch == "" {
+			utils.WriteError(w, http.StatusPreconditionRequired, "If-Match header is required")
+			return
+		}
+
+		v, found, err := version(r)
+		if err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+		// A missing resource is left to the handler, which knows whether to
+		// create it or answer 404
+		failed := func(w http.ResponseWriter, r *http.Request) {
+			if v, found, err := version(r); err == nil && found {
+				w.Header().Set("ETag", ETag(v))
+			}
+			current(&statusWriter{ResponseWriter: w, status: http.StatusPreconditionFailed}, r)
+		}
+		if found && !matches(ifMatch, ETag(v)) {
+			failed(w, r)
+			return
+		}
+
+		if found {
+			ctx := context.WithValue(r.Context(), preconditionContextKey, &precondition{version: v, failed: failed})
+			r = r.WithContext(ctx)
+		}
+		handler(&etagWriter{ResponseWriter: w, r: r, version: version}, r)
+	}
+}
+
+// ClaimMatchedVersion returns the version IfMatch checked the request
+// against, which its write must still find. Only the first caller gets it:
+// that is the write to the resource the request addresses, and the writes it
+// makes in turn, like publishing a draft onto a page, are not guarded.
+func ClaimMatchedVersion(ctx context.Context) (string, bool) {
+	p, ok := ctx.Value(preconditionContextKey).(*precondition)
+	if !ok || !p.claimed.CompareAndSwap(false, true) {
+		return "", false
+	}
+	return p.version, true
+}
+
+// WritePreconditionFailed answers ErrPreconditionFailed like IfMatch answers
+// a stale If-Match, and reports whether err was one
+func WritePreconditionFailed(w http.ResponseWriter, r *http.Request, err error) bool {
+	if !errors.Is(err, ErrPreconditionFailed) {
+		return false
+	}
+	if p, ok := r.Context().Value(preconditionContextKey).(*precondition); ok {
+		p.failed(w, r)
+		return true
+	}
+	utils.WriteError(w, http.StatusPreconditionFailed, err.Error())
+	return true
+}
+
+// matches reports whether an If-Match header lists etag or is a wildcard
+func matches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeak is matches with the weak comparison If-None-Match uses, where
+// W/"x" and "x" are the same tag
+func matchesWeak(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// statusWriter replaces the success status of a handler with status
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if sw.wroteHeader {
+		return
+	}
+	sw.wroteHeader = true
+	if code >= 200 && code < 300 {
+		code = sw.status
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	if !sw.wroteHeader {
+		sw.WriteHeader(http.StatusOK)
+	}
+	return sw.ResponseWriter.Write(b)
+}
+
+// etagWriter sets the ETag of the resource once a write succeeded, just
+// before the status line is sent
+type etagWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	version     VersionFunc
+	wroteHeader bool
+}
+
+func (ew *etagWriter) WriteHeader(code int) {
+	if ew.wroteHeader {
+		return
+	}
+	ew.wroteHeader = true
+	if code >= 200 && code < 300 {
+		if v, found, err := ew.version(ew.r); err == nil && found {
+			ew.Header().Set("ETag", ETag(v))
+		}
+	}
+	ew.ResponseWriter.WriteHeader(code)
+}
+
+func (ew *etagWriter) Write(b []byte) (int, error) {
+	if !ew.wroteHeader {
+		ew.WriteHeader(http.StatusOK)
+	}
+	return ew.ResponseWriter.Write(b)
+}
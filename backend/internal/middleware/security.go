@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"context"
+	"net"
 	"net/http"
+	"net/netip"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
 	"golang.org/x/time/rate"
 )
 
@@ -121,7 +125,7 @@ func (rl *RateLimiter) getLimiter(ip string) *rate.Limiter {
 
 func (rl *RateLimiter) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := getClientIP(r)
+		ip := ClientIP(r)
 		limiter := rl.getLimiter(ip)
 
 		if !limiter.Allow() {
@@ -133,27 +137,94 @@ func (rl *RateLimiter) Handler(next http.Handler) http.Handler {
 	})
 }
 
-// getClientIP extracts the client IP from the request
-func getClientIP(r *http.Request) string {
+const clientIPContextKey = contextKey("client_ip")
+
+// TrustedProxies resolves the client IP of each request for ClientIP. The
+// forwarding headers are only believed when the peer is in trusted, and
+// X-Forwarded-For is read right to left, skipping trusted hops, so a client
+// cannot pick the address it is rate limited and locked out under.
+//
+// The first request carrying forwarding headers from a peer that is not
+// trusted is logged: behind a reverse proxy missing from TRUSTED_PROXIES,
+// every client would share the proxy's address.
+func TrustedProxies(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		var warnUntrusted sync.Once
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Forwarded-For") != "" || r.Header.Get("X-Real-IP") != "" {
+				if peer := remoteIP(r); !isTrusted(peer, trusted) {
+					warnUntrusted.Do(func() {
+						logger.Warn("Ignoring forwarding headers from a peer outside TRUSTED_PROXIES; if it is a reverse proxy, add it there", "peer", peer)
+					})
+				}
+			}
+			ctx := context.WithValue(r.Context(), clientIPContextKey, resolveClientIP(r, trusted))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP returns the client IP resolved by TrustedProxies, or the peer
+// address outside of it
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPContextKey).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	ip := remoteIP(r)
+	if !isTrusted(ip, trusted) {
+		return ip
+	}
+
 	// Check X-Forwarded-For header (proxy/load balancer)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				// Garbled by the client; the last trusted hop saw the request
+				return ip
+			}
+			if !isTrusted(hop, trusted) {
+				return hop
+			}
+			ip = hop
 		}
+		return ip
 	}
 
 	// Check X-Real-IP header (proxy)
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); xri != "" {
+		if _, err := netip.ParseAddr(xri); err == nil {
+			return xri
+		}
+	}
+	return ip
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
+	return host
+}
 
-	// Fallback to RemoteAddr
-	ip := r.RemoteAddr
-	if colon := strings.LastIndex(ip, ":"); colon != -1 {
-		ip = ip[:colon]
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
 	}
-	return ip
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
 }
 
 // Input Sanitization
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -44,7 +43,7 @@ const (
 )
 
 func (ct *CareerType) Scan(value interface{}) error {
-	str, ok := value.(string)
+	str, ok := scanText(value)
 	if !ok {
 		return fmt.Errorf("cannot scan CareerType from %T", value)
 	}
@@ -56,6 +55,11 @@ func (ct CareerType) Value() (driver.Value, error) {
 	return string(ct), nil
 }
 
+// Valid reports whether ct is one of the career_type enum values
+func (ct CareerType) Valid() bool {
+	return ct == Education || ct == Job
+}
+
 type CareerJourney struct {
 	gorm.Model
 	ID          uint       `json:"id" gorm:"primaryKey"`
@@ -81,6 +85,7 @@ const (
 	Beginner     SkillLevel = "Beginner"
 	Intermediate SkillLevel = "Intermediate"
 	Advanced     SkillLevel = "Advanced"
+	Expert       SkillLevel = "Expert"
 )
 
 const (
@@ -90,7 +95,7 @@ const (
 )
 
 func (sl *SkillLevel) Scan(value interface{}) error {
-	str, ok := value.(string)
+	str, ok := scanText(value)
 	if !ok {
 		return fmt.Errorf("cannot scan SkillLevel from %T", value)
 	}
@@ -99,7 +104,7 @@ func (sl *SkillLevel) Scan(value interface{}) error {
 }
 
 func (sl *Cateogry) Scan(value interface{}) error {
-	str, ok := value.(string)
+	str, ok := scanText(value)
 	if !ok {
 		return fmt.Errorf("cannot scan Category from %T", value)
 	}
@@ -115,15 +120,29 @@ func (sl Cateogry) Value() (driver.Value, error) {
 	return string(sl), nil
 }
 
+// Valid reports whether sl is one of the skill_level enum values
+func (sl SkillLevel) Valid() bool {
+	switch sl {
+	case Beginner, Intermediate, Advanced, Expert:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether c is one of the category enum values
+func (c Cateogry) Valid() bool {
+	return c == Backend || c == Frontend || c == Other
+}
+
 type TechnicalSkills struct {
 	gorm.Model
-	ID               uint           `json:"id" gorm:"primaryKey"`
-	Name             string         `json:"name"`
-	Description      string         `json:"description"`
-	Specialities     pq.StringArray `json:"specialities" gorm:"type:text[]"`
-	Level            SkillLevel     `json:"level" gorm:"type:skill_level"`
-	Category         Cateogry       `json:"category" gorm:"type:category"`
-	YearOfExperience int            `json:"year_of_experience"`
-	UpdatedAt        time.Time      `json:"updated_at"`
-	CreatedAt        time.Time      `json:"created_at"`
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Specialities     StringList `json:"specialities"`
+	Level            SkillLevel `json:"level" gorm:"type:skill_level"`
+	Category         Cateogry   `json:"category" gorm:"type:category"`
+	YearOfExperience int        `json:"year_of_experience"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// API Key
+// ============================================================================
+
+// APIKeyTokenPrefix marks a bearer credential as an API key rather than a JWT
+const APIKeyTokenPrefix = "pfk_"
+
+// APIKey is a personal access token for automation clients. The full token is
+// shown once at creation; only its SHA-256 hash is stored.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"index;not null"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"uniqueIndex;not null"`
+	KeyHash    string     `json:"-" gorm:"not null"`
+	Scopes     StringList `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
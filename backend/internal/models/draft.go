@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ============================================================================
+// Draft
+// ============================================================================
+
+// Draft is an unpublished edit of a page or item. Payload holds the section
+// DTO as JSON. EntityID is the item being edited; it is nil for pages and
+// for new items until they are published. PreviousRevisionID is the revision
+// that was live before publishing, which unpublishing restores.
+type Draft struct {
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	Kind               string     `json:"kind" gorm:"index;not null"`
+	EntityID           *uint      `json:"entity_id"`
+	Payload            string     `json:"payload" gorm:"not null"`
+	AuthorID           *uint      `json:"author_id"`
+	PublishAt          *time.Time `json:"publish_at"`
+	UnpublishAt        *time.Time `json:"unpublish_at"`
+	PublishedAt        *time.Time `json:"published_at"`
+	UnpublishedAt      *time.Time `json:"unpublished_at"`
+	PreviousRevisionID *uint      `json:"previous_revision_id"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
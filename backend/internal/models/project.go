@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -39,7 +38,7 @@ const (
 )
 
 func (pt *ProjectType) Scan(value interface{}) error {
-	str, ok := value.(string)
+	str, ok := scanText(value)
 	if !ok {
 		return fmt.Errorf("cannot scan ProjectType from %T", value)
 	}
@@ -52,7 +51,7 @@ func (pt ProjectType) Value() (driver.Value, error) {
 }
 
 func (ct *ContributionType) Scan(value interface{}) error {
-	str, ok := value.(string)
+	str, ok := scanText(value)
 	if !ok {
 		return fmt.Errorf("cannot scan ContributionType from %T", value)
 	}
@@ -64,13 +63,23 @@ func (ct ContributionType) Value() (driver.Value, error) {
 	return string(ct), nil
 }
 
+// Valid reports whether pt is one of the project_type enum values
+func (pt ProjectType) Valid() bool {
+	return pt == Web || pt == Mobile || pt == MachineLearning
+}
+
+// Valid reports whether ct is one of the contribution_type enum values
+func (ct ContributionType) Valid() bool {
+	return ct == Personal || ct == Team
+}
+
 type Project struct {
 	gorm.Model
 	ID           uint             `json:"id" gorm:"primaryKey"`
 	Name         string           `json:"name"`
-	ImageUrls    pq.StringArray   `json:"imageUrls" gorm:"type:text[]"`
+	ImageUrls    StringList       `json:"imageUrls"`
 	Description  string           `json:"description"`
-	TechStack    pq.StringArray   `json:"techStack" gorm:"type:text[]"`
+	TechStack    StringList       `json:"techStack"`
 	GithubLink   string           `json:"githubLink"`
 	Type         ProjectType      `json:"type" gorm:"type:project_type"`
 	Contribution ContributionType `json:"contribution" gorm:"type:contribution_type"`
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ============================================================================
+// Revision
+// ============================================================================
+
+// RevisionAction is the kind of write a revision recorded
+type RevisionAction string
+
+const (
+	RevisionCreate   RevisionAction = "create"
+	RevisionUpdate   RevisionAction = "update"
+	RevisionDelete   RevisionAction = "delete"
+	RevisionRestore  RevisionAction = "restore"
+	RevisionRollback RevisionAction = "rollback"
+)
+
+// Revision is an immutable copy of a content row taken right after a write.
+// Snapshot holds the whole row as JSON, so any revision can be restored on
+// its own.
+type Revision struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Kind      string         `json:"kind" gorm:"index:idx_revisions_kind_entity,priority:1;not null"`
+	EntityID  uint           `json:"entity_id" gorm:"index:idx_revisions_kind_entity,priority:2;not null"`
+	Action    RevisionAction `json:"action" gorm:"not null"`
+	AuthorID  *uint          `json:"author_id"`
+	Snapshot  string         `json:"snapshot" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+}
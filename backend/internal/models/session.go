@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ============================================================================
+// Session
+// ============================================================================
+
+// Session records a signed-in device. Its ID is the refresh token family, so
+// every access token issued for the session carries it in the fam claim.
+type Session struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"index;not null"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"index"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// StringList is a list of strings stored as a native text[] on PostgreSQL and
+// as a JSON array in a text column on SQLite
+type StringList []string
+
+// GormDataType lets Gorm parse the field; see GormDBDataType for the column
+func (StringList) GormDataType() string {
+	return "text"
+}
+
+// GormDBDataType picks the column type for the connected database
+func (StringList) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "text[]"
+	}
+	return "text"
+}
+
+// GormValue encodes the list for the connected database
+func (l StringList) GormValue(_ context.Context, db *gorm.DB) clause.Expr {
+	value, err := l.encode(db.Dialector.Name())
+	if err != nil {
+		_ = db.AddError(err)
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{value}}
+}
+
+// Value encodes the list as a PostgreSQL array literal; queries built by Gorm
+// go through GormValue instead
+func (l StringList) Value() (driver.Value, error) {
+	return pq.StringArray(l).Value()
+}
+
+// Scan decodes either a PostgreSQL array literal or a JSON array
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	str, ok := scanText(value)
+	if !ok {
+		return fmt.Errorf("cannot scan StringList from %T", value)
+	}
+
+	if strings.HasPrefix(str, "[") {
+		var list []string
+		if err := json.Unmarshal([]byte(str), &list); err != nil {
+			return fmt.Errorf("cannot scan StringList: %w", err)
+		}
+		*l = list
+		return nil
+	}
+
+	var arr pq.StringArray
+	if err := arr.Scan(str); err != nil {
+		return err
+	}
+	*l = StringList(arr)
+	return nil
+}
+
+func (l StringList) encode(dialect string) (interface{}, error) {
+	if l == nil {
+		return nil, nil
+	}
+	if dialect == "postgres" {
+		return pq.StringArray(l).Value()
+	}
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// scanText accepts the string forms drivers return for text columns: pgx
+// returns string, SQLite may return []byte
+func scanText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
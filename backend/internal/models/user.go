@@ -0,0 +1,128 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// User
+// ============================================================================
+
+type UserRole string
+
+const (
+	RoleOwner     UserRole = "owner"
+	RoleEditor    UserRole = "editor"
+	RoleModerator UserRole = "moderator"
+)
+
+// Valid reports whether the role is one of the known roles
+func (ur UserRole) Valid() bool {
+	switch ur {
+	case RoleOwner, RoleEditor, RoleModerator:
+		return true
+	}
+	return false
+}
+
+func (ur *UserRole) Scan(value interface{}) error {
+	str, ok := scanText(value)
+	if !ok {
+		return fmt.Errorf("cannot scan UserRole from %T", value)
+	}
+	*ur = UserRole(str)
+	return nil
+}
+
+func (ur UserRole) Value() (driver.Value, error) {
+	return string(ur), nil
+}
+
+// ============================================================================
+// Scopes
+// ============================================================================
+
+// Scope names a permission checked per route. Users get scopes from their
+// role; API keys carry an explicit subset of their owner's scopes.
+type Scope string
+
+const (
+	ScopeHeroWrite         Scope = "hero:write"
+	ScopeAboutWrite        Scope = "about:write"
+	ScopeProjectWrite      Scope = "project:write"
+	ScopeTestimonyWrite    Scope = "testimony:write"
+	ScopeTestimonyModerate Scope = "testimony:moderate"
+	ScopeUsersManage       Scope = "users:manage"
+)
+
+// AllScopes lists every known scope
+var AllScopes = []Scope{
+	ScopeHeroWrite,
+	ScopeAboutWrite,
+	ScopeProjectWrite,
+	ScopeTestimonyWrite,
+	ScopeTestimonyModerate,
+	ScopeUsersManage,
+}
+
+var roleScopes = map[UserRole][]Scope{
+	RoleOwner: AllScopes,
+	RoleEditor: {
+		ScopeHeroWrite,
+		ScopeAboutWrite,
+		ScopeProjectWrite,
+		ScopeTestimonyWrite,
+		ScopeTestimonyModerate,
+	},
+	RoleModerator: {
+		ScopeTestimonyModerate,
+	},
+}
+
+// Scopes returns the scopes granted by the role
+func (ur UserRole) Scopes() []Scope {
+	return roleScopes[ur]
+}
+
+// HasScope reports whether the role grants the scope
+func (ur UserRole) HasScope(scope Scope) bool {
+	return slices.Contains(roleScopes[ur], scope)
+}
+
+// Valid reports whether the scope is one of the known scopes
+func (s Scope) Valid() bool {
+	return slices.Contains(AllScopes, s)
+}
+
+type User struct {
+	gorm.Model
+	ID           uint     `json:"id" gorm:"primaryKey"`
+	Email        string   `json:"email" gorm:"uniqueIndex;not null"`
+	Name         string   `json:"name"`
+	PasswordHash string   `json:"-" gorm:"not null"`
+	Role         UserRole `json:"role" gorm:"type:user_role;not null"`
+	// TOTPSecret is set during enrollment and only enforced once TOTPEnabled
+	TOTPSecret      string    `json:"-"`
+	TOTPEnabled     bool      `json:"totp_enabled"`
+	TOTPLastCounter int64     `json:"-"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ============================================================================
+// Recovery Code
+// ============================================================================
+
+// RecoveryCode is a single-use second factor; only its SHA-256 hash is stored
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	CodeHash  string     `json:"-" gorm:"uniqueIndex;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,90 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+type Handler struct {
+	service *Service
+	// loginRedirectURL is the CMS page that receives ?ticket= or ?error=
+	loginRedirectURL string
+}
+
+func NewHandler(service *Service, loginRedirectURL string) *Handler {
+	return &Handler{
+		service:          service,
+		loginRedirectURL: loginRedirectURL,
+	}
+}
+
+// GetProviders handles GET /auth/oidc
+func (h *Handler) GetProviders(w http.ResponseWriter, r *http.Request) {
+	providers := h.service.Providers()
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"length": len(providers),
+		"data":   providers,
+	})
+}
+
+// Login handles GET /auth/oidc/{provider}/login by redirecting to the provider
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.service.BeginLogin(r.Context(), chi.URLParam(r, "provider"))
+	if err != nil {
+		if errors.Is(err, ErrUnknownProvider) {
+			utils.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.WriteServerError(w, err)
+		return
+	}
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback handles GET /auth/oidc/{provider}/callback and sends the browser
+// back to the CMS with a login ticket
+func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	query := r.URL.Query()
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		logger.Warn("OIDC provider returned an error", "provider", provider, "error", providerErr)
+		h.redirect(w, r, "error", "access_denied")
+		return
+	}
+
+	ticket, err := h.service.CompleteLogin(r.Context(), provider, query.Get("code"), query.Get("state"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnknownProvider):
+			utils.WriteError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, ErrInvalidState):
+			h.redirect(w, r, "error", "invalid_state")
+		case errors.Is(err, ErrEmailNotVerified), errors.Is(err, ErrUserNotAllowed):
+			h.redirect(w, r, "error", "not_allowed")
+		default:
+			logger.Error("OIDC login failed", "provider", provider, "error", err)
+			h.redirect(w, r, "error", "login_failed")
+		}
+		return
+	}
+
+	h.redirect(w, r, "ticket", ticket)
+}
+
+func (h *Handler) redirect(w http.ResponseWriter, r *http.Request, key, value string) {
+	target, err := url.Parse(h.loginRedirectURL)
+	if err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+	query := target.Query()
+	query.Set(key, value)
+	target.RawQuery = query.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
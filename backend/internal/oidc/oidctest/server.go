@@ -0,0 +1,241 @@
+// Package oidctest provides a stand-in OpenID Connect provider built on
+// httptest for exercising the OIDC login flow without a real provider.
+//
+// The provider signs every authorization request in as a single configurable
+// user, so a test only has to follow the redirects:
+//
+//	idp := oidctest.NewServer()
+//	defer idp.Close()
+//	idp.SetUser("owner@example.com", true)
+//	provider := oidc.NewProvider(idp.ProviderConfig("test", callbackURL), idp.Client())
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+const keyID = "oidctest"
+
+// authorization is what the provider remembers between /authorize and /token
+type authorization struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	verified      bool
+}
+
+// Server is a minimal OpenID Connect provider supporting the authorization
+// code flow with PKCE (S256)
+type Server struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string
+
+	key *rsa.PrivateKey
+
+	mu       sync.Mutex
+	email    string
+	verified bool
+	codes    map[string]authorization
+}
+
+// NewServer starts a provider. It panics if the signing key cannot be
+// generated, like httptest.NewServer does on listener errors.
+func NewServer() *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic("oidctest: generating key: " + err.Error())
+	}
+
+	s := &Server{
+		ClientID:     "oidctest-client",
+		ClientSecret: "oidctest-secret",
+		key:          key,
+		email:        "owner@example.com",
+		verified:     true,
+		codes:        make(map[string]authorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Issuer is the issuer identifier, also the base URL of the server
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// SetUser sets the identity returned for the following logins
+func (s *Server) SetUser(email string, verified bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.email = email
+	s.verified = verified
+}
+
+// ProviderConfig returns the configuration pointing at this server
+func (s *Server) ProviderConfig(name, redirectURL string) config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Name:         name,
+		Issuer:       s.Issuer(),
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		RedirectURL:  redirectURL,
+	}
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.Issuer(),
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// authorize approves every request immediately and redirects back with a code
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if q.Get("client_id") != s.ClientID || redirectURI == "" {
+		http.Error(w, "invalid client", http.StatusBadRequest)
+		return
+	}
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("state", q.Get("state"))
+
+	if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		params.Set("error", "invalid_request")
+	} else {
+		code := randomString()
+		s.mu.Lock()
+		s.codes[code] = authorization{
+			redirectURI:   redirectURI,
+			nonce:         q.Get("nonce"),
+			codeChallenge: q.Get("code_challenge"),
+			email:         s.email,
+			verified:      s.verified,
+		}
+		s.mu.Unlock()
+		params.Set("code", code)
+	}
+
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+// token redeems a code once, checking the client and the PKCE verifier
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.ClientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.ClientSecret)) != 1 {
+		tokenError(w, "invalid_client")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	auth, found := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	if r.PostForm.Get("grant_type") != "authorization_code" || !found ||
+		auth.redirectURI != r.PostForm.Get("redirect_uri") || auth.codeChallenge != challenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            s.Issuer(),
+		"sub":            auth.email,
+		"aud":            s.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.email,
+		"email_verified": auth.verified,
+	})
+	idToken.Header["kid"] = keyID
+	signed, err := idToken.SignedString(s.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, utils.JWKSet{Keys: []utils.JWK{{
+		Kty: "RSA",
+		Kid: keyID,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,124 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"github.com/othersidedrl/portfolio/backend/internal/user"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("unknown identity provider")
+	ErrInvalidState     = errors.New("invalid or expired login state")
+	ErrEmailNotVerified = errors.New("identity provider did not verify the email")
+	ErrUserNotAllowed   = errors.New("no admin account for this email")
+)
+
+// Service runs the authorization code flow with PKCE and turns a verified
+// email into a login ticket for an existing admin user
+type Service struct {
+	providers map[string]*Provider
+	states    StateStore
+	users     *user.Service
+	tokens    token.Store
+}
+
+func NewService(providers []*Provider, states StateStore, users *user.Service, tokens token.Store) *Service {
+	byName := make(map[string]*Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &Service{
+		providers: byName,
+		states:    states,
+		users:     users,
+		tokens:    tokens,
+	}
+}
+
+// Providers lists the configured provider names
+func (s *Service) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginLogin stores a fresh state, nonce and PKCE verifier and returns the
+// provider URL to send the browser to
+func (s *Service) BeginLogin(ctx context.Context, name string) (string, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	state, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.states.Save(ctx, state, LoginState{Provider: name, Nonce: nonce, Verifier: verifier}); err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(ctx, state, nonce, codeChallenge(verifier))
+}
+
+// CompleteLogin handles the provider callback and returns a login ticket
+func (s *Service) CompleteLogin(ctx context.Context, name, code, state string) (string, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	// The state is single-use
+	saved, err := s.states.Take(ctx, state)
+	if err != nil {
+		return "", err
+	}
+	if state == "" || saved == nil || saved.Provider != name {
+		return "", ErrInvalidState
+	}
+
+	claims, err := provider.Exchange(ctx, code, saved.Verifier, saved.Nonce)
+	if err != nil {
+		return "", err
+	}
+	if claims.Email == "" || !claims.Verified() {
+		return "", ErrEmailNotVerified
+	}
+
+	u, err := s.users.GetUserByEmail(ctx, claims.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			logger.Warn("OIDC login for unknown email", "provider", name, "email", claims.Email)
+			return "", ErrUserNotAllowed
+		}
+		return "", err
+	}
+
+	return s.tokens.IssueLoginTicket(ctx, strconv.FormatUint(uint64(u.ID), 10))
+}
+
+// codeChallenge derives the S256 PKCE challenge from the verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
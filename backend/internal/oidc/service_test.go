@@ -0,0 +1,215 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/oidc/oidctest"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"github.com/othersidedrl/portfolio/backend/internal/user"
+	"github.com/redis/go-redis/v9"
+)
+
+const callbackURL = "http://cms.test/auth/oidc/test/callback"
+
+type testLogin struct {
+	service *Service
+	idp     *oidctest.Server
+	states  StateStore
+	tokens  token.Store
+	owner   *user.UserItemDto
+}
+
+// forEachBackend runs test with the login state and tickets kept in Redis,
+// then in memory
+func forEachBackend(t *testing.T, test func(t *testing.T, l *testLogin)) {
+	t.Run("redis", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		test(t, newTestLogin(t, NewRedisStateStore(client), token.NewRedisStore(client, time.Minute, time.Hour)))
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, newTestLogin(t, NewMemoryStateStore(), token.NewMemoryStore(time.Minute, time.Hour)))
+	})
+}
+
+// newTestLogin returns a service signing in through a stand-in provider
+// named "test", for a database holding one owner, owner@example.com
+func newTestLogin(t *testing.T, states StateStore, tokens token.Store) *testLogin {
+	t.Helper()
+	ctx := context.Background()
+
+	idp := oidctest.NewServer()
+	t.Cleanup(idp.Close)
+
+	users := user.NewService(user.NewGormUserRepository(repotest.NewSQLiteDB(t)), "test")
+	owner, err := users.CreateUser(ctx, &user.CreateUserDto{
+		Email:    "owner@example.com",
+		Name:     "Owner",
+		Password: "correct horse battery",
+		Role:     models.RoleOwner,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	providers := []*Provider{
+		NewProvider(idp.ProviderConfig("test", callbackURL), idp.Client()),
+		NewProvider(idp.ProviderConfig("other", callbackURL), idp.Client()),
+	}
+	return &testLogin{
+		service: NewService(providers, states, users, tokens),
+		idp:     idp,
+		states:  states,
+		tokens:  tokens,
+		owner:   owner,
+	}
+}
+
+// authorize starts a login at provider and follows it through the stand-in
+// provider, returning the code and state it sends back to the callback
+func (l *testLogin) authorize(t *testing.T, provider string) (code, state string) {
+	t.Helper()
+
+	authURL, err := l.service.BeginLogin(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	client := l.idp.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	res, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	res.Body.Close()
+
+	callback, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || res.StatusCode != http.StatusFound {
+		t.Fatalf("authorize answered %d with Location %q", res.StatusCode, res.Header.Get("Location"))
+	}
+	if got := callback.Scheme + "://" + callback.Host + callback.Path; got != callbackURL {
+		t.Fatalf("redirected to %s, want %s", got, callbackURL)
+	}
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func TestLoginIssuesTicketForOwner(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, l *testLogin) {
+		ctx := context.Background()
+
+		code, state := l.authorize(t, "test")
+		ticket, err := l.service.CompleteLogin(ctx, "test", code, state)
+		if err != nil {
+			t.Fatalf("CompleteLogin: %v", err)
+		}
+
+		userID, err := l.tokens.ConsumeLoginTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("ConsumeLoginTicket: %v", err)
+		}
+		if want := strconv.FormatUint(uint64(l.owner.ID), 10); userID != want {
+			t.Errorf("ticket is for user %s, want %s", userID, want)
+		}
+		if _, err := l.tokens.ConsumeLoginTicket(ctx, ticket); !errors.Is(err, token.ErrInvalidLoginTicket) {
+			t.Errorf("redeeming the ticket again error = %v, want ErrInvalidLoginTicket", err)
+		}
+	})
+}
+
+func TestLoginRejectsBadState(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, l *testLogin) {
+		ctx := context.Background()
+
+		code, state := l.authorize(t, "test")
+		for name, state := range map[string]string{"empty": "", "unknown": "not-a-state", "other provider's": state} {
+			provider := "test"
+			if name == "other provider's" {
+				provider = "other"
+			}
+			if _, err := l.service.CompleteLogin(ctx, provider, code, state); !errors.Is(err, ErrInvalidState) {
+				t.Errorf("CompleteLogin with an %s state error = %v, want ErrInvalidState", name, err)
+			}
+		}
+
+		// A state is used up by its first callback, even a rejected one
+		if _, err := l.service.CompleteLogin(ctx, "test", code, state); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("CompleteLogin with a used state error = %v, want ErrInvalidState", err)
+		}
+
+		code, state = l.authorize(t, "test")
+		if _, err := l.service.CompleteLogin(ctx, "test", code, state); err != nil {
+			t.Fatalf("CompleteLogin: %v", err)
+		}
+		if _, err := l.service.CompleteLogin(ctx, "test", code, state); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("replaying a callback error = %v, want ErrInvalidState", err)
+		}
+
+		if _, err := l.service.CompleteLogin(ctx, "missing", code, state); !errors.Is(err, ErrUnknownProvider) {
+			t.Errorf("CompleteLogin at an unknown provider error = %v, want ErrUnknownProvider", err)
+		}
+	})
+}
+
+func TestLoginRejectsNonceMismatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, l *testLogin) {
+		ctx := context.Background()
+
+		// The provider signs the nonce it was sent, so a different one saved
+		// with the state stands in for a replayed id_token
+		code, state := l.authorize(t, "test")
+		saved, err := l.states.Take(ctx, state)
+		if err != nil || saved == nil {
+			t.Fatalf("Take = %v, %v, want the saved state", saved, err)
+		}
+		saved.Nonce = "another-nonce"
+		if err := l.states.Save(ctx, state, *saved); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		ticket, err := l.service.CompleteLogin(ctx, "test", code, state)
+		if err == nil || !strings.Contains(err.Error(), "nonce mismatch") {
+			t.Fatalf("CompleteLogin = %q, %v, want a nonce mismatch", ticket, err)
+		}
+	})
+}
+
+func TestLoginRejectsUnverifiedEmail(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, l *testLogin) {
+		l.idp.SetUser("owner@example.com", false)
+
+		code, state := l.authorize(t, "test")
+		if _, err := l.service.CompleteLogin(context.Background(), "test", code, state); !errors.Is(err, ErrEmailNotVerified) {
+			t.Errorf("CompleteLogin error = %v, want ErrEmailNotVerified", err)
+		}
+	})
+}
+
+func TestLoginRejectsUnknownEmail(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, l *testLogin) {
+		l.idp.SetUser("stranger@example.com", true)
+
+		code, state := l.authorize(t, "test")
+		if _, err := l.service.CompleteLogin(context.Background(), "test", code, state); !errors.Is(err, ErrUserNotAllowed) {
+			t.Errorf("CompleteLogin error = %v, want ErrUserNotAllowed", err)
+		}
+	})
+}
+
+func TestBeginLoginRejectsUnknownProvider(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, l *testLogin) {
+		if _, err := l.service.BeginLogin(context.Background(), "missing"); !errors.Is(err, ErrUnknownProvider) {
+			t.Errorf("BeginLogin error = %v, want ErrUnknownProvider", err)
+		}
+	})
+}
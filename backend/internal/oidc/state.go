@@ -0,0 +1,102 @@
+package oidc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// stateTTL bounds how long a user may spend at the provider
+const stateTTL = 10 * time.Minute
+
+// LoginState is what a login saves for its callback, under the state
+// parameter it sends to the provider
+type LoginState struct {
+	Provider string
+	Nonce    string
+	Verifier string
+}
+
+// StateStore keeps the state of the logins waiting for their callback
+type StateStore interface {
+	// Save stores state under raw for stateTTL
+	Save(ctx context.Context, raw string, state LoginState) error
+	// Take returns and deletes the state saved under raw, so it is only
+	// used once, or returns nil if there is none
+	Take(ctx context.Context, raw string) (*LoginState, error)
+}
+
+func stateKey(raw string) string {
+	return "oidc_state:" + utils.HashToken(raw)
+}
+
+// RedisStateStore keeps the login states in Redis, so the callback may reach
+// another instance than the login
+type RedisStateStore struct {
+	client *redis.Client
+}
+
+func NewRedisStateStore(client *redis.Client) *RedisStateStore {
+	return &RedisStateStore{client: client}
+}
+
+func (s *RedisStateStore) Save(ctx context.Context, raw string, state LoginState) error {
+	key := stateKey(raw)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, "provider", state.Provider, "nonce", state.Nonce, "verifier", state.Verifier)
+	pipe.Expire(ctx, key, stateTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisStateStore) Take(ctx context.Context, raw string) (*LoginState, error) {
+	// Read and delete atomically
+	key := stateKey(raw)
+	pipe := s.client.TxPipeline()
+	get := pipe.HGetAll(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	saved := get.Val()
+	if len(saved) == 0 {
+		return nil, nil
+	}
+	return &LoginState{
+		Provider: saved["provider"],
+		Nonce:    saved["nonce"],
+		Verifier: saved["verifier"],
+	}, nil
+}
+
+// MemoryStateStore keeps the login states in the memory of this instance,
+// for deployments without Redis. It is safe for concurrent use.
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states *utils.ExpiringMap[LoginState] // by state hash
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{states: utils.NewExpiringMap[LoginState]()}
+}
+
+func (s *MemoryStateStore) Save(ctx context.Context, raw string, state LoginState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states.Set(utils.HashToken(raw), state, stateTTL)
+	return nil
+}
+
+func (s *MemoryStateStore) Take(ctx context.Context, raw string) (*LoginState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states.Delete(utils.HashToken(raw))
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
@@ -2,11 +2,14 @@ package project
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"gorm.io/gorm"
 )
 
 type Handler struct {
@@ -27,13 +30,23 @@ func (h *Handler) GetProjectPage(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(page)
 }
 
+// UpdateProjectPage applies a JSON merge patch to the project page
 func (h *Handler) UpdateProjectPage(w http.ResponseWriter, r *http.Request) {
-	var body ProjectPageDto
-	if err := utils.DecodeBody(r, &body); err != nil {
+	body, err := h.service.GetProjectPage(r.Context())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		body = &ProjectPageDto{}
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if err := h.service.UpdateProjectPage(r.Context(), &body); err != nil {
+	if err := h.service.UpdateProjectPage(r.Context(), body); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -57,6 +70,25 @@ func (h *Handler) GetProjects(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+	project, err := h.service.GetProjectByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
 func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	var body ProjectItemDto
 	if err := utils.DecodeBody(r, &body); err != nil {
@@ -64,6 +96,10 @@ func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.service.CreateProject(r.Context(), &body); err != nil {
+		if errors.Is(err, ErrInvalidProject) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -72,18 +108,35 @@ func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Successfully created a project"})
 }
 
+// UpdateProject applies a JSON merge patch to a project: fields the body
+// leaves out are kept and fields it sets, even to an empty value, are written
 func (h *Handler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "Invalid project ID", http.StatusBadRequest)
 		return
 	}
-	var body ProjectItemDto
-	if err := utils.DecodeBody(r, &body); err != nil {
+	body, err := h.service.GetProjectByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if err := h.service.UpdateProject(r.Context(), &body, uint(id)); err != nil {
+	if err := h.service.UpdateProject(r.Context(), body, uint(id)); err != nil {
+		if errors.Is(err, ErrInvalidProject) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -99,6 +152,9 @@ func (h *Handler) DeleteProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.service.DeleteProject(r.Context(), uint(id)); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
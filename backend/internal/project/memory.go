@@ -0,0 +1,111 @@
+package project
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// MemoryProjectRepository is an in-memory implementation of ProjectRepository
+// for tests and database-free runs. It is safe for concurrent use.
+type MemoryProjectRepository struct {
+	mu       sync.RWMutex
+	page     *ProjectPageDto
+	projects map[uint]ProjectItemDto
+	nextID   uint
+}
+
+// NewMemoryProjectRepository creates an empty MemoryProjectRepository
+func NewMemoryProjectRepository() *MemoryProjectRepository {
+	return &MemoryProjectRepository{
+		projects: map[uint]ProjectItemDto{},
+		nextID:   1,
+	}
+}
+
+func (r *MemoryProjectRepository) GetProjectPage(ctx context.Context) (*ProjectPageDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.page == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	page := *r.page
+	return &page, nil
+}
+
+func (r *MemoryProjectRepository) UpdateProjectPage(ctx context.Context, data *ProjectPageDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	page := *data
+	r.page = &page
+	return nil
+}
+
+func (r *MemoryProjectRepository) GetProjects(ctx context.Context) (*ProjectDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var projects []ProjectItemDto
+	for _, id := range slices.Sorted(maps.Keys(r.projects)) {
+		projects = append(projects, cloneProject(r.projects[id]))
+	}
+	return &ProjectDto{Projects: projects}, nil
+}
+
+func (r *MemoryProjectRepository) GetProjectByID(ctx context.Context, id uint) (*ProjectItemDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, ok := r.projects[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	project = cloneProject(project)
+	return &project, nil
+}
+
+func (r *MemoryProjectRepository) CreateProject(ctx context.Context, data *ProjectItemDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	project := cloneProject(*data)
+	project.ID = int(r.nextID)
+	r.projects[r.nextID] = project
+	r.nextID++
+	data.ID = project.ID
+	return nil
+}
+
+// UpdateProject overwrites every field of the project, including empty ones.
+// Unknown ids are ignored.
+func (r *MemoryProjectRepository) UpdateProject(ctx context.Context, data *ProjectItemDto, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[id]; !ok {
+		return nil
+	}
+	project := cloneProject(*data)
+	project.ID = int(id)
+	r.projects[id] = project
+	return nil
+}
+
+func (r *MemoryProjectRepository) DeleteProject(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.projects, id)
+	return nil
+}
+
+func cloneProject(p ProjectItemDto) ProjectItemDto {
+	p.ImageUrls = slices.Clone(p.ImageUrls)
+	p.TechStack = slices.Clone(p.TechStack)
+	return p
+}
@@ -1,6 +1,10 @@
 package project
 
-import "github.com/othersidedrl/portfolio/backend/internal/models"
+import (
+	"fmt"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+)
 
 type ProjectPageDto struct {
 	Title       string `json:"title"`
@@ -19,6 +23,20 @@ type ProjectItemDto struct {
 	ProjectLink  string                  `json:"projectLink"`
 }
 
+// Validate checks what the database would reject, so a create or a patch
+// that sets an empty name or unknown enum answers 400 rather than 500
+func (p *ProjectItemDto) Validate() error {
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidProject)
+	case !p.Type.Valid():
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidProject, p.Type)
+	case !p.Contribution.Valid():
+		return fmt.Errorf("%w: unknown contribution %q", ErrInvalidProject, p.Contribution)
+	}
+	return nil
+}
+
 type ProjectDto struct {
 	Projects []ProjectItemDto `json:"projects"`
 }
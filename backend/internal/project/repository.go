@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
 	"gorm.io/gorm"
 )
 
@@ -11,6 +13,8 @@ type ProjectRepository interface {
 	GetProjectPage(ctx context.Context) (*ProjectPageDto, error)
 	UpdateProjectPage(ctx context.Context, data *ProjectPageDto) error
 	GetProjects(ctx context.Context) (*ProjectDto, error)
+	GetProjectByID(ctx context.Context, id uint) (*ProjectItemDto, error)
+	// CreateProject stores a new project and sets data.ID to its id
 	CreateProject(ctx context.Context, data *ProjectItemDto) error
 	UpdateProject(ctx context.Context, data *ProjectItemDto, id uint) error
 	DeleteProject(ctx context.Context, id uint) error
@@ -36,16 +40,22 @@ func (r *GormProjectRepository) GetProjectPage(ctx context.Context) (*ProjectPag
 }
 
 func (r *GormProjectRepository) UpdateProjectPage(ctx context.Context, data *ProjectPageDto) error {
-	var page models.ProjectPage
-	if err := r.db.WithContext(ctx).First(&page).Error; err != nil {
-		return r.db.WithContext(ctx).Create(&models.ProjectPage{
-			Title:       data.Title,
-			Description: data.Description,
-		}).Error
-	}
-	page.Title = data.Title
-	page.Description = data.Description
-	return r.db.WithContext(ctx).Save(&page).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockPage(ctx, tx, &models.ProjectPage{}); err != nil {
+			return err
+		}
+		var page models.ProjectPage
+		if err := tx.First(&page).Error; err != nil {
+			page = models.ProjectPage{
+				Title:       data.Title,
+				Description: data.Description,
+			}
+			return revision.RecordWrite(tx.Create(&page), revision.KindProjectPage, models.RevisionCreate, &page)
+		}
+		page.Title = data.Title
+		page.Description = data.Description
+		return revision.RecordWrite(tx.Save(&page), revision.KindProjectPage, models.RevisionUpdate, &page)
+	})
 }
 
 func (r *GormProjectRepository) GetProjects(ctx context.Context) (*ProjectDto, error) {
@@ -70,33 +80,70 @@ func (r *GormProjectRepository) GetProjects(ctx context.Context) (*ProjectDto, e
 	return &ProjectDto{Projects: dtoProjects}, nil
 }
 
-func (r *GormProjectRepository) CreateProject(ctx context.Context, data *ProjectItemDto) error {
-	project := models.Project{
-		Name:         data.Name,
-		ImageUrls:    data.ImageUrls,
-		Description:  data.Description,
-		TechStack:    data.TechStack,
-		GithubLink:   data.GithubLink,
-		Type:         data.Type,
-		Contribution: data.Contribution,
-		ProjectLink:  data.ProjectLink,
+func (r *GormProjectRepository) GetProjectByID(ctx context.Context, id uint) (*ProjectItemDto, error) {
+	var p models.Project
+	if err := r.db.WithContext(ctx).First(&p, id).Error; err != nil {
+		return nil, err
 	}
-	return r.db.WithContext(ctx).Create(&project).Error
+	return &ProjectItemDto{
+		ID:           int(p.ID),
+		Name:         p.Name,
+		ImageUrls:    p.ImageUrls,
+		Description:  p.Description,
+		TechStack:    p.TechStack,
+		GithubLink:   p.GithubLink,
+		Type:         p.Type,
+		Contribution: p.Contribution,
+		ProjectLink:  p.ProjectLink,
+	}, nil
+}
+
+func (r *GormProjectRepository) CreateProject(ctx context.Context, data *ProjectItemDto) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		project := models.Project{
+			Name:         data.Name,
+			ImageUrls:    data.ImageUrls,
+			Description:  data.Description,
+			TechStack:    data.TechStack,
+			GithubLink:   data.GithubLink,
+			Type:         data.Type,
+			Contribution: data.Contribution,
+			ProjectLink:  data.ProjectLink,
+		}
+		if err := revision.RecordWrite(tx.Create(&project), revision.KindProjects, models.RevisionCreate, &project); err != nil {
+			return err
+		}
+		data.ID = int(project.ID)
+		return nil
+	})
 }
 
+// UpdateProject overwrites every field of the project, including empty ones
 func (r *GormProjectRepository) UpdateProject(ctx context.Context, data *ProjectItemDto, id uint) error {
-	return r.db.WithContext(ctx).Model(&models.Project{}).Where("id = ?", id).Updates(&models.Project{
-		Name:         data.Name,
-		ImageUrls:    data.ImageUrls,
-		Description:  data.Description,
-		TechStack:    data.TechStack,
-		GithubLink:   data.GithubLink,
-		Type:         data.Type,
-		Contribution: data.Contribution,
-		ProjectLink:  data.ProjectLink,
-	}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Project{}, id); err != nil {
+			return err
+		}
+		write := tx.Model(&models.Project{}).Where("id = ?", id).
+			Select("*").Omit("id", "created_at", "deleted_at")
+		return revision.RecordWrite(write.Updates(&models.Project{
+			Name:         data.Name,
+			ImageUrls:    data.ImageUrls,
+			Description:  data.Description,
+			TechStack:    data.TechStack,
+			GithubLink:   data.GithubLink,
+			Type:         data.Type,
+			Contribution: data.Contribution,
+			ProjectLink:  data.ProjectLink,
+		}), revision.KindProjects, models.RevisionUpdate, &models.Project{ID: id})
+	})
 }
 
 func (r *GormProjectRepository) DeleteProject(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Unscoped().Delete(&models.Project{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Project{}, id); err != nil {
+			return err
+		}
+		return revision.RecordWrite(tx.Where("id = ?", id).Delete(&models.Project{}), revision.KindProjects, models.RevisionDelete, &models.Project{ID: id})
+	})
 }
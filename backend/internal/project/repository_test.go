@@ -0,0 +1,20 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+)
+
+func TestMemoryProjectRepository(t *testing.T) {
+	repotest.RunProjectRepository(t, func(t *testing.T) project.ProjectRepository {
+		return project.NewMemoryProjectRepository()
+	})
+}
+
+func TestGormProjectRepository(t *testing.T) {
+	repotest.RunProjectRepository(t, func(t *testing.T) project.ProjectRepository {
+		return project.NewGormProjectRepository(repotest.NewSQLiteDB(t))
+	})
+}
@@ -2,14 +2,21 @@ package project
 
 import (
 	"context"
+	"errors"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
 )
 
+var ErrInvalidProject = errors.New("invalid project")
+
 type Service struct {
-	repo ProjectRepository
+	repo       ProjectRepository
+	invalidate cache.Invalidator
 }
 
-func NewService(repo ProjectRepository) *Service {
-	return &Service{repo}
+// NewService calls invalidate after the project page or a project changed
+func NewService(repo ProjectRepository, invalidate cache.Invalidator) *Service {
+	return &Service{repo, invalidate}
 }
 
 func (s *Service) GetProjectPage(ctx context.Context) (*ProjectPageDto, error) {
@@ -17,21 +24,47 @@ func (s *Service) GetProjectPage(ctx context.Context) (*ProjectPageDto, error) {
 }
 
 func (s *Service) UpdateProjectPage(ctx context.Context, data *ProjectPageDto) error {
-	return s.repo.UpdateProjectPage(ctx, data)
+	if err := s.repo.UpdateProjectPage(ctx, data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagProjectPage)
+	return nil
 }
 
 func (s *Service) GetProjects(ctx context.Context) (*ProjectDto, error) {
 	return s.repo.GetProjects(ctx)
 }
 
+func (s *Service) GetProjectByID(ctx context.Context, id uint) (*ProjectItemDto, error) {
+	return s.repo.GetProjectByID(ctx, id)
+}
+
 func (s *Service) CreateProject(ctx context.Context, data *ProjectItemDto) error {
-	return s.repo.CreateProject(ctx, data)
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.CreateProject(ctx, data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagProjects)
+	return nil
 }
 
 func (s *Service) UpdateProject(ctx context.Context, data *ProjectItemDto, id uint) error {
-	return s.repo.UpdateProject(ctx, data, id)
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateProject(ctx, data, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagProjects)
+	return nil
 }
 
 func (s *Service) DeleteProject(ctx context.Context, id uint) error {
-	return s.repo.DeleteProject(ctx, id)
+	if err := s.repo.DeleteProject(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagProjects)
+	return nil
 }
@@ -0,0 +1,62 @@
+package project_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+)
+
+func TestProjectWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	inv := &repotest.Invalidations{}
+	s := project.NewService(project.NewMemoryProjectRepository(), inv.Invalidate)
+
+	if err := s.UpdateProjectPage(ctx, &project.ProjectPageDto{Title: "Projects"}); err != nil {
+		t.Fatalf("UpdateProjectPage: %v", err)
+	}
+	item := &project.ProjectItemDto{Name: "Portfolio", Type: models.Web, Contribution: models.Personal}
+	if err := s.CreateProject(ctx, item); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	item.Name = "Portfolio v2"
+	if err := s.UpdateProject(ctx, item, 1); err != nil {
+		t.Fatalf("UpdateProject: %v", err)
+	}
+	if err := s.DeleteProject(ctx, 1); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+
+	want := repotest.Invalidations{{cache.TagProjectPage}, {cache.TagProjects}, {cache.TagProjects}, {cache.TagProjects}}
+	if !slices.EqualFunc(*inv, want, slices.Equal) {
+		t.Errorf("invalidated %v, want %v", *inv, want)
+	}
+}
+
+func TestProjectRejectsInvalidItems(t *testing.T) {
+	ctx := context.Background()
+	inv := &repotest.Invalidations{}
+	s := project.NewService(project.NewMemoryProjectRepository(), inv.Invalidate)
+
+	for _, item := range []project.ProjectItemDto{
+		{Type: models.Web, Contribution: models.Personal},
+		{Name: "Portfolio", Contribution: models.Personal},
+		{Name: "Portfolio", Type: "Desktop", Contribution: models.Personal},
+		{Name: "Portfolio", Type: models.Web},
+	} {
+		if err := s.CreateProject(ctx, &item); !errors.Is(err, project.ErrInvalidProject) {
+			t.Errorf("CreateProject(%+v) = %v, want ErrInvalidProject", item, err)
+		}
+		if err := s.UpdateProject(ctx, &item, 1); !errors.Is(err, project.ErrInvalidProject) {
+			t.Errorf("UpdateProject(%+v) = %v, want ErrInvalidProject", item, err)
+		}
+	}
+	if len(*inv) != 0 {
+		t.Errorf("invalidated %v for rejected writes", *inv)
+	}
+}
@@ -0,0 +1,237 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"gorm.io/gorm"
+)
+
+// RunAboutRepository checks that an AboutRepository behaves like the GORM one
+func RunAboutRepository(t *testing.T, newRepo func(t *testing.T) about.AboutRepository) {
+	ctx := context.Background()
+
+	t.Run("FindEmpty", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.Find(ctx); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("Find on empty repository: got %v, want gorm.ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("UpdateKeepsMissingCards", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.Update(ctx, &about.AboutPageDto{
+			Description: "Hello",
+			Available:   true,
+			Cards: []about.CardDto{
+				{Title: "One", Description: "1"},
+				{Title: "Two", Description: "2"},
+				{Title: "Three", Description: "3"},
+				{Title: "Four", Description: "4"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		err = repo.Update(ctx, &about.AboutPageDto{
+			Description: "Updated",
+			GithubLink:  "https://github.com/example",
+			Cards:       []about.CardDto{{Title: "First", Description: "changed"}},
+		})
+		if err != nil {
+			t.Fatalf("second Update: %v", err)
+		}
+
+		got, err := repo.Find(ctx)
+		if err != nil {
+			t.Fatalf("Find: %v", err)
+		}
+		if got.Description != "Updated" || got.GithubLink != "https://github.com/example" || got.Available {
+			t.Errorf("page = %+v, want the second update's fields", got)
+		}
+		wantCards := []about.CardDto{
+			{Title: "First", Description: "changed"},
+			{Title: "Two", Description: "2"},
+			{Title: "Three", Description: "3"},
+			{Title: "Four", Description: "4"},
+		}
+		if !slices.Equal(got.Cards, wantCards) {
+			t.Errorf("cards = %+v, want %+v", got.Cards, wantCards)
+		}
+	})
+
+	t.Run("SkillsEmpty", func(t *testing.T) {
+		repo := newRepo(t)
+		got, err := repo.GetTechnicalSkills(ctx)
+		if err != nil {
+			t.Fatalf("GetTechnicalSkills: %v", err)
+		}
+		if len(got.Skills) != 0 {
+			t.Errorf("got %d skills, want none", len(got.Skills))
+		}
+	})
+
+	t.Run("SkillLifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+		var createdIDs []uint
+		for _, name := range []string{"Go", "TypeScript"} {
+			skill := &about.SkillItemDto{
+				Name:             name,
+				Description:      name + " description",
+				Specialities:     []string{"APIs", "CLIs"},
+				Level:            "Expert",
+				Category:         "Backend",
+				YearOfExperience: 5,
+			}
+			if err := repo.CreateTechnicalSkill(ctx, skill); err != nil {
+				t.Fatalf("CreateTechnicalSkill(%s): %v", name, err)
+			}
+			createdIDs = append(createdIDs, skill.ID)
+		}
+
+		skills := mustSkills(t, repo)
+		if len(skills) != 2 || skills[0].Name != "Go" || skills[1].Name != "TypeScript" {
+			t.Fatalf("skills = %+v, want Go then TypeScript", skills)
+		}
+		if skills[0].ID == 0 || !ascendingIDs([]uint{skills[0].ID, skills[1].ID}) {
+			t.Errorf("skill ids = %d, %d, want increasing non-zero ids", skills[0].ID, skills[1].ID)
+		}
+		if createdIDs[0] != skills[0].ID || createdIDs[1] != skills[1].ID {
+			t.Errorf("CreateTechnicalSkill set ids %v, want the stored %d, %d", createdIDs, skills[0].ID, skills[1].ID)
+		}
+		if !sameStrings(skills[0].Specialities, []string{"APIs", "CLIs"}) || skills[0].Level != "Expert" {
+			t.Errorf("skill = %+v, want the created fields", skills[0])
+		}
+
+		// Every field is written, so empty ones clear what was stored
+		goID := skills[0].ID
+		update := skills[0]
+		update.Level = "Advanced"
+		update.Description = ""
+		update.YearOfExperience = 0
+		if err := repo.UpdateTechnicalSkill(ctx, &update, goID); err != nil {
+			t.Fatalf("UpdateTechnicalSkill: %v", err)
+		}
+		updated := mustSkills(t, repo)[0]
+		if updated.Level != "Advanced" || updated.Name != "Go" || updated.Description != "" ||
+			updated.YearOfExperience != 0 || !sameStrings(updated.Specialities, []string{"APIs", "CLIs"}) {
+			t.Errorf("after update skill = %+v, want cleared description and experience", updated)
+		}
+
+		if err := repo.UpdateTechnicalSkill(ctx, &about.SkillItemDto{Name: "Ghost"}, 9999); err != nil {
+			t.Errorf("UpdateTechnicalSkill on unknown id: %v", err)
+		}
+		if err := repo.DeleteTechnicalSkill(ctx, goID); err != nil {
+			t.Fatalf("DeleteTechnicalSkill: %v", err)
+		}
+		if err := repo.DeleteTechnicalSkill(ctx, 9999); err != nil {
+			t.Errorf("DeleteTechnicalSkill on unknown id: %v", err)
+		}
+		skills = mustSkills(t, repo)
+		if len(skills) != 1 || skills[0].Name != "TypeScript" {
+			t.Errorf("after delete skills = %+v, want only TypeScript", skills)
+		}
+		if got, err := repo.GetTechnicalSkillByID(ctx, skills[0].ID); err != nil || got.Name != "TypeScript" {
+			t.Errorf("GetTechnicalSkillByID = %+v, %v, want TypeScript", got, err)
+		}
+		if _, err := repo.GetTechnicalSkillByID(ctx, goID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("GetTechnicalSkillByID after delete: got %v, want gorm.ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("CareerLifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+		var createdIDs []uint
+		for _, title := range []string{"University", "First job"} {
+			career := &about.CareerItemDto{
+				Title:       title,
+				Affiliation: "Somewhere",
+				Location:    "Jakarta",
+				Type:        "Education",
+				StartedAt:   "2020",
+				EndedAt:     "2024",
+			}
+			if err := repo.CreateCareer(ctx, career); err != nil {
+				t.Fatalf("CreateCareer(%s): %v", title, err)
+			}
+			createdIDs = append(createdIDs, career.ID)
+		}
+
+		careers := mustCareers(t, repo)
+		if len(careers) != 2 || careers[0].Title != "University" || careers[1].Title != "First job" {
+			t.Fatalf("careers = %+v, want University then First job", careers)
+		}
+		if createdIDs[0] != careers[0].ID || createdIDs[1] != careers[1].ID {
+			t.Errorf("CreateCareer set ids %v, want the stored %d, %d", createdIDs, careers[0].ID, careers[1].ID)
+		}
+
+		jobID := careers[1].ID
+		update := careers[1]
+		update.Type = "Job"
+		update.EndedAt = ""
+		if err := repo.UpdateCareer(ctx, &update, jobID); err != nil {
+			t.Fatalf("UpdateCareer: %v", err)
+		}
+		job := mustCareers(t, repo)[1]
+		if job.Type != "Job" || job.EndedAt != "" || job.Title != "First job" || job.StartedAt != "2020" {
+			t.Errorf("after update career = %+v, want a job with no end", job)
+		}
+
+		if err := repo.DeleteCareer(ctx, careers[0].ID); err != nil {
+			t.Fatalf("DeleteCareer: %v", err)
+		}
+		careers = mustCareers(t, repo)
+		if len(careers) != 1 || careers[0].ID != jobID {
+			t.Errorf("after delete careers = %+v, want only the job", careers)
+		}
+		if got, err := repo.GetCareerByID(ctx, jobID); err != nil || got.Title != "First job" || got.Type != "Job" {
+			t.Errorf("GetCareerByID = %+v, %v, want the job", got, err)
+		}
+		if _, err := repo.GetCareerByID(ctx, 9999); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("GetCareerByID on unknown id: got %v, want gorm.ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("ConcurrentCreates", func(t *testing.T) {
+		repo := newRepo(t)
+		runConcurrently(t, func(i int) error {
+			return repo.CreateTechnicalSkill(ctx, &about.SkillItemDto{
+				Name:     fmt.Sprintf("skill-%d", i),
+				Level:    "Beginner",
+				Category: "Other",
+			})
+		})
+
+		skills := mustSkills(t, repo)
+		ids := make([]uint, len(skills))
+		for i, s := range skills {
+			ids[i] = s.ID
+		}
+		if len(skills) != concurrentWriters || !ascendingIDs(ids) {
+			t.Errorf("got %d skills with ids %v, want %d distinct ids", len(skills), ids, concurrentWriters)
+		}
+	})
+}
+
+func mustSkills(t *testing.T, repo about.AboutRepository) []about.SkillItemDto {
+	t.Helper()
+	got, err := repo.GetTechnicalSkills(context.Background())
+	if err != nil {
+		t.Fatalf("GetTechnicalSkills: %v", err)
+	}
+	return got.Skills
+}
+
+func mustCareers(t *testing.T, repo about.AboutRepository) []about.CareerItemDto {
+	t.Helper()
+	got, err := repo.GetCareers(context.Background())
+	if err != nil {
+		t.Fatalf("GetCareers: %v", err)
+	}
+	return got.Careers
+}
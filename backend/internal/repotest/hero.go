@@ -0,0 +1,87 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/hero"
+	"gorm.io/gorm"
+)
+
+// RunHeroRepository checks that a HeroRepository behaves like the GORM one
+func RunHeroRepository(t *testing.T, newRepo func(t *testing.T) hero.HeroRepository) {
+	ctx := context.Background()
+
+	t.Run("FindEmpty", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.Find(ctx); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("Find on empty repository: got %v, want gorm.ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("UpdateCreatesThenReplaces", func(t *testing.T) {
+		repo := newRepo(t)
+		first := &hero.HeroPageDto{
+			Name:      "Ada",
+			Rank:      "Senior",
+			Title:     "Engineer",
+			Subtitle:  "Builds things",
+			ImageUrl1: "https://example.com/1.png",
+			Hobbies:   []string{"chess", "tea, green", `say "hi"`},
+		}
+		if err := repo.Update(ctx, first); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.Find(ctx)
+		if err != nil {
+			t.Fatalf("Find: %v", err)
+		}
+		assertHero(t, got, first)
+
+		second := &hero.HeroPageDto{Name: "Grace", Title: "Admiral"}
+		if err := repo.Update(ctx, second); err != nil {
+			t.Fatalf("second Update: %v", err)
+		}
+		got, err = repo.Find(ctx)
+		if err != nil {
+			t.Fatalf("Find after second Update: %v", err)
+		}
+		assertHero(t, got, second)
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Update(ctx, &hero.HeroPageDto{Name: "Ada", Hobbies: []string{"chess"}}); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.Find(ctx)
+		if err != nil {
+			t.Fatalf("Find: %v", err)
+		}
+		got.Name = "changed"
+		got.Hobbies[0] = "changed"
+
+		again, err := repo.Find(ctx)
+		if err != nil {
+			t.Fatalf("Find: %v", err)
+		}
+		if again.Name != "Ada" || again.Hobbies[0] != "chess" {
+			t.Errorf("mutating a returned hero changed the stored one: %+v", again)
+		}
+	})
+}
+
+func assertHero(t *testing.T, got, want *hero.HeroPageDto) {
+	t.Helper()
+
+	wantCopy, gotCopy := *want, *got
+	wantCopy.Hobbies, gotCopy.Hobbies = nil, nil
+	if !reflect.DeepEqual(gotCopy, wantCopy) {
+		t.Errorf("hero = %+v, want %+v", gotCopy, wantCopy)
+	}
+	if !sameStrings(got.Hobbies, want.Hobbies) {
+		t.Errorf("hobbies = %q, want %q", got.Hobbies, want.Hobbies)
+	}
+}
@@ -0,0 +1,15 @@
+package repotest
+
+import (
+	"context"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+)
+
+// Invalidations records the tags a service invalidates, one entry per call
+type Invalidations [][]cache.Tag
+
+// Invalidate records tags; pass it to a service as its invalidate function
+func (i *Invalidations) Invalidate(ctx context.Context, tags ...cache.Tag) {
+	*i = append(*i, tags)
+}
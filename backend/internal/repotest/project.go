@@ -0,0 +1,149 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"gorm.io/gorm"
+)
+
+// RunProjectRepository checks that a ProjectRepository behaves like the GORM
+// one
+func RunProjectRepository(t *testing.T, newRepo func(t *testing.T) project.ProjectRepository) {
+	ctx := context.Background()
+
+	t.Run("PageEmptyThenUpdated", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetProjectPage(ctx); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetProjectPage on empty repository: got %v, want gorm.ErrRecordNotFound", err)
+		}
+		for _, title := range []string{"Projects", "Selected work"} {
+			if err := repo.UpdateProjectPage(ctx, &project.ProjectPageDto{Title: title, Description: "desc"}); err != nil {
+				t.Fatalf("UpdateProjectPage: %v", err)
+			}
+			got, err := repo.GetProjectPage(ctx)
+			if err != nil {
+				t.Fatalf("GetProjectPage: %v", err)
+			}
+			if got.Title != title || got.Description != "desc" {
+				t.Errorf("page = %+v, want title %q", got, title)
+			}
+		}
+	})
+
+	t.Run("ProjectLifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+		if got := mustProjects(t, repo); len(got) != 0 {
+			t.Fatalf("projects on empty repository = %+v, want none", got)
+		}
+
+		for _, name := range []string{"Portfolio", "Chat app"} {
+			err := repo.CreateProject(ctx, &project.ProjectItemDto{
+				Name:         name,
+				ImageUrls:    []string{"https://example.com/a.png"},
+				TechStack:    []string{"Go", "Next.js"},
+				Type:         models.Web,
+				Contribution: models.Personal,
+			})
+			if err != nil {
+				t.Fatalf("CreateProject(%s): %v", name, err)
+			}
+		}
+
+		projects := mustProjects(t, repo)
+		if len(projects) != 2 || projects[0].Name != "Portfolio" || projects[1].Name != "Chat app" {
+			t.Fatalf("projects = %+v, want Portfolio then Chat app", projects)
+		}
+		if !ascendingIDs([]int{projects[0].ID, projects[1].ID}) || projects[0].ID == 0 {
+			t.Errorf("project ids = %d, %d, want increasing non-zero ids", projects[0].ID, projects[1].ID)
+		}
+		if !sameStrings(projects[0].TechStack, []string{"Go", "Next.js"}) || projects[0].Type != models.Web {
+			t.Errorf("project = %+v, want the created fields", projects[0])
+		}
+
+		// Every field is written, so empty ones clear what was stored
+		chatID := uint(projects[1].ID)
+		update := projects[1]
+		update.TechStack = []string{}
+		update.ImageUrls = nil
+		update.Contribution = models.Team
+		if err := repo.UpdateProject(ctx, &update, chatID); err != nil {
+			t.Fatalf("UpdateProject: %v", err)
+		}
+		chat := mustProjects(t, repo)[1]
+		if len(chat.TechStack) != 0 || len(chat.ImageUrls) != 0 || chat.Contribution != models.Team ||
+			chat.Name != "Chat app" || chat.Type != models.Web || chat.ID != int(chatID) {
+			t.Errorf("after update project = %+v, want cleared tech stack and images", chat)
+		}
+
+		if err := repo.UpdateProject(ctx, &project.ProjectItemDto{Name: "Ghost"}, 9999); err != nil {
+			t.Errorf("UpdateProject on unknown id: %v", err)
+		}
+		if err := repo.DeleteProject(ctx, uint(projects[0].ID)); err != nil {
+			t.Fatalf("DeleteProject: %v", err)
+		}
+		projects = mustProjects(t, repo)
+		if len(projects) != 1 || projects[0].Name != "Chat app" {
+			t.Errorf("after delete projects = %+v, want only Chat app", projects)
+		}
+		if got, err := repo.GetProjectByID(ctx, chatID); err != nil || got.Name != "Chat app" || got.Contribution != models.Team {
+			t.Errorf("GetProjectByID = %+v, %v, want Chat app", got, err)
+		}
+		if _, err := repo.GetProjectByID(ctx, 9999); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("GetProjectByID on unknown id: got %v, want gorm.ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		repo := newRepo(t)
+		created := &project.ProjectItemDto{
+			Name:         "Portfolio",
+			TechStack:    []string{"Go"},
+			Type:         models.Web,
+			Contribution: models.Personal,
+		}
+		if err := repo.CreateProject(ctx, created); err != nil {
+			t.Fatalf("CreateProject: %v", err)
+		}
+		if listed := mustProjects(t, repo)[0].ID; created.ID != listed {
+			t.Errorf("CreateProject set id %d, want the stored %d", created.ID, listed)
+		}
+		mustProjects(t, repo)[0].TechStack[0] = "changed"
+		if got := mustProjects(t, repo)[0].TechStack; !sameStrings(got, []string{"Go"}) {
+			t.Errorf("mutating a returned project changed the stored one: %q", got)
+		}
+	})
+
+	t.Run("ConcurrentCreates", func(t *testing.T) {
+		repo := newRepo(t)
+		runConcurrently(t, func(i int) error {
+			return repo.CreateProject(ctx, &project.ProjectItemDto{
+				Name:         fmt.Sprintf("project-%d", i),
+				Type:         models.Mobile,
+				Contribution: models.Team,
+			})
+		})
+
+		projects := mustProjects(t, repo)
+		ids := make([]int, len(projects))
+		for i, p := range projects {
+			ids[i] = p.ID
+		}
+		if len(projects) != concurrentWriters || !ascendingIDs(ids) {
+			t.Errorf("got %d projects with ids %v, want %d distinct ids", len(projects), ids, concurrentWriters)
+		}
+	})
+}
+
+func mustProjects(t *testing.T, repo project.ProjectRepository) []project.ProjectItemDto {
+	t.Helper()
+	got, err := repo.GetProjects(context.Background())
+	if err != nil {
+		t.Fatalf("GetProjects: %v", err)
+	}
+	return got.Projects
+}
@@ -0,0 +1,102 @@
+// Package repotest is a conformance suite for the content repositories. Every
+// implementation of an interface, GORM-backed or in-memory, must pass the same
+// Run* function, e.g. from a test in the owning package:
+//
+//	func TestMemoryHeroRepository(t *testing.T) {
+//		repotest.RunHeroRepository(t, func(t *testing.T) hero.HeroRepository {
+//			return hero.NewMemoryHeroRepository()
+//		})
+//	}
+//
+//	func TestGormHeroRepository(t *testing.T) {
+//		repotest.RunHeroRepository(t, func(t *testing.T) hero.HeroRepository {
+//			return hero.NewGormHeroRepository(repotest.NewSQLiteDB(t))
+//		})
+//	}
+//
+// The factory is called once per subtest and must return an empty repository.
+// The package also holds the fakes the service tests share, e.g. Invalidations.
+package repotest
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// concurrentWriters is how many goroutines the concurrency checks start
+const concurrentWriters = 16
+
+// NewSQLiteDB opens a migrated, empty SQLite database in a temporary file
+// that is removed when the test ends
+func NewSQLiteDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db, err := database.Open(&config.Config{
+		DBDriver:   "sqlite",
+		SQLitePath: filepath.Join(t.TempDir(), "repotest.db"),
+	})
+	if err != nil {
+		t.Fatalf("opening SQLite database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("opening SQLite database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrating SQLite database: %v", err)
+	}
+	return db
+}
+
+// runConcurrently calls fn from concurrentWriters goroutines at once and
+// fails the test if any call returns an error
+func runConcurrently(t *testing.T, fn func(i int) error) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentWriters)
+	for i := range concurrentWriters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(i); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent write: %v", err)
+	}
+}
+
+// sameStrings compares lists by content; drivers may return nil or an empty
+// slice for an empty list
+func sameStrings(a, b []string) bool {
+	return len(a) == 0 && len(b) == 0 || slices.Equal(a, b)
+}
+
+// ascendingIDs reports whether ids are strictly increasing, i.e. listed in
+// creation order
+func ascendingIDs[T ~int | ~uint](ids []T) bool {
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			return false
+		}
+	}
+	return true
+}
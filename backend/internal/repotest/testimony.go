@@ -0,0 +1,188 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+	"gorm.io/gorm"
+)
+
+// RunTestimonyRepository checks that a TestimonyRepository behaves like the
+// GORM one
+func RunTestimonyRepository(t *testing.T, newRepo func(t *testing.T) testimony.TestimonyRepository) {
+	ctx := context.Background()
+
+	t.Run("PageEmptyThenUpdated", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetTestimonyPage(ctx); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetTestimonyPage on empty repository: got %v, want gorm.ErrRecordNotFound", err)
+		}
+		for _, title := range []string{"Kind words", "What people say"} {
+			if err := repo.UpdateTestimonyPage(ctx, &testimony.TestimonyPageDto{Title: title}); err != nil {
+				t.Fatalf("UpdateTestimonyPage: %v", err)
+			}
+			got, err := repo.GetTestimonyPage(ctx)
+			if err != nil {
+				t.Fatalf("GetTestimonyPage: %v", err)
+			}
+			if got.Title != title {
+				t.Errorf("page title = %q, want %q", got.Title, title)
+			}
+		}
+	})
+
+	t.Run("CreateIsUnapproved", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{
+			Name:        "Linus",
+			Rating:      5,
+			Description: "Great work",
+			Approved:    true,
+		})
+		if err != nil {
+			t.Fatalf("CreateTestimony: %v", err)
+		}
+
+		all := mustTestimonies(t, repo)
+		if len(all) != 1 || all[0].ID == 0 || all[0].Name != "Linus" || all[0].Rating != 5 {
+			t.Fatalf("testimonies = %+v, want the created testimony", all)
+		}
+		if all[0].Approved {
+			t.Error("new testimony is approved, want unapproved")
+		}
+		if approved := mustApproved(t, repo); len(approved) != 0 {
+			t.Errorf("approved testimonies = %+v, want none", approved)
+		}
+	})
+
+	t.Run("ApproveAndRevoke", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, name := range []string{"First", "Second"} {
+			if err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: name, Rating: 4}); err != nil {
+				t.Fatalf("CreateTestimony(%s): %v", name, err)
+			}
+		}
+		all := mustTestimonies(t, repo)
+		if len(all) != 2 || all[0].Name != "First" || !ascendingIDs([]int{all[0].ID, all[1].ID}) {
+			t.Fatalf("testimonies = %+v, want First then Second", all)
+		}
+		secondID := uint(all[1].ID)
+
+		if err := repo.ApproveTestimony(ctx, &testimony.ApproveTestimonyDto{Approved: true}, secondID); err != nil {
+			t.Fatalf("ApproveTestimony: %v", err)
+		}
+		approved := mustApproved(t, repo)
+		if len(approved) != 1 || approved[0].Name != "Second" || !approved[0].Approved {
+			t.Fatalf("approved testimonies = %+v, want only Second", approved)
+		}
+
+		if err := repo.ApproveTestimony(ctx, &testimony.ApproveTestimonyDto{Approved: false}, secondID); err != nil {
+			t.Fatalf("ApproveTestimony(false): %v", err)
+		}
+		if approved := mustApproved(t, repo); len(approved) != 0 {
+			t.Errorf("approved testimonies after revoking = %+v, want none", approved)
+		}
+	})
+
+	t.Run("UpdateWritesZeroFields", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada", Rating: 3, Description: "Nice"}); err != nil {
+			t.Fatalf("CreateTestimony: %v", err)
+		}
+		id := uint(mustTestimonies(t, repo)[0].ID)
+
+		// The approval flow saves the summary and approval through UpdateTestimony
+		update := mustTestimonies(t, repo)[0]
+		update.AISummary = "Short"
+		update.Approved = true
+		if err := repo.UpdateTestimony(ctx, &update, id); err != nil {
+			t.Fatalf("UpdateTestimony: %v", err)
+		}
+		got, err := repo.GetTestimonyByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetTestimonyByID: %v", err)
+		}
+		if got.AISummary != "Short" || !got.Approved || got.Name != "Ada" || got.Rating != 3 || got.Description != "Nice" {
+			t.Errorf("after approving update testimony = %+v", got)
+		}
+
+		// A rating of 0 and approved set to false are written too
+		update = *got
+		update.Rating = 0
+		update.Approved = false
+		if err := repo.UpdateTestimony(ctx, &update, id); err != nil {
+			t.Fatalf("UpdateTestimony: %v", err)
+		}
+		if got, err = repo.GetTestimonyByID(ctx, id); err != nil {
+			t.Fatalf("GetTestimonyByID: %v", err)
+		}
+		if got.Rating != 0 || got.Approved || got.AISummary != "Short" {
+			t.Errorf("after clearing update testimony = %+v, want rating 0 and not approved", got)
+		}
+
+		if err := repo.UpdateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ghost"}, 9999); err != nil {
+			t.Errorf("UpdateTestimony on unknown id: %v", err)
+		}
+	})
+
+	t.Run("GetByIDAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetTestimonyByID(ctx, 9999); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetTestimonyByID on unknown id: got %v, want gorm.ErrRecordNotFound", err)
+		}
+		if err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada"}); err != nil {
+			t.Fatalf("CreateTestimony: %v", err)
+		}
+		id := uint(mustTestimonies(t, repo)[0].ID)
+
+		if err := repo.DeleteTestimony(ctx, id); err != nil {
+			t.Fatalf("DeleteTestimony: %v", err)
+		}
+		if err := repo.DeleteTestimony(ctx, id); err != nil {
+			t.Errorf("DeleteTestimony twice: %v", err)
+		}
+		if _, err := repo.GetTestimonyByID(ctx, id); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("GetTestimonyByID after delete: got %v, want gorm.ErrRecordNotFound", err)
+		}
+		if all := mustTestimonies(t, repo); len(all) != 0 {
+			t.Errorf("testimonies after delete = %+v, want none", all)
+		}
+	})
+
+	t.Run("ConcurrentCreates", func(t *testing.T) {
+		repo := newRepo(t)
+		runConcurrently(t, func(i int) error {
+			return repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: fmt.Sprintf("guest-%d", i)})
+		})
+
+		all := mustTestimonies(t, repo)
+		ids := make([]int, len(all))
+		for i, item := range all {
+			ids[i] = item.ID
+		}
+		if len(all) != concurrentWriters || !ascendingIDs(ids) {
+			t.Errorf("got %d testimonies with ids %v, want %d distinct ids", len(all), ids, concurrentWriters)
+		}
+	})
+}
+
+func mustTestimonies(t *testing.T, repo testimony.TestimonyRepository) []testimony.TestimonyItemDto {
+	t.Helper()
+	got, err := repo.GetTestimonies(context.Background())
+	if err != nil {
+		t.Fatalf("GetTestimonies: %v", err)
+	}
+	return got.Testimonies
+}
+
+func mustApproved(t *testing.T, repo testimony.TestimonyRepository) []testimony.TestimonyItemDto {
+	t.Helper()
+	got, err := repo.GetApprovedTestimonies(context.Background())
+	if err != nil {
+		t.Fatalf("GetApprovedTestimonies: %v", err)
+	}
+	return got.Testimonies
+}
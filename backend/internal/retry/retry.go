@@ -0,0 +1,54 @@
+// Package retry retries startup work, like connecting to a database that is
+// still booting, with bounded exponential backoff
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+)
+
+// Policy bounds the retries: the first retry waits InitialBackoff, every
+// following one twice as long up to MaxBackoff, and after MaxAttempts
+// failures the last error is returned
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Do calls fn until it succeeds, the attempts run out or ctx is cancelled.
+// name says what is being attempted in the log, e.g. "connect to Redis".
+func Do(ctx context.Context, policy Policy, name string, fn func(ctx context.Context) error) error {
+	attempts := max(policy.MaxAttempts, 1)
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("Succeeded after retrying", "operation", name, "attempts", attempt)
+			}
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("%s: giving up after %d attempts: %w", name, attempt, err)
+		}
+
+		logger.Warn("Attempt failed, retrying", "operation", name, "attempt", attempt, "retry_in", backoff.String(), "error", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: %w", name, ctx.Err())
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
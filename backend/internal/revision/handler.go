@@ -0,0 +1,181 @@
+package revision
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+// Handler serves the history of each kind on its own route, so that routes
+// can require the scope that edits that kind
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetRevisions handles GET /admin/revisions/{kind}?entity_id=&limit=
+func (h *Handler) GetRevisions(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entityID, ok := optionalID(w, r, "entity_id")
+		if !ok {
+			return
+		}
+		limit := 0
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				utils.WriteError(w, http.StatusBadRequest, "Invalid limit")
+				return
+			}
+			limit = n
+		}
+
+		list, err := h.service.GetRevisions(r.Context(), kind, entityID, limit)
+		if err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"length": len(list.Revisions),
+			"data":   list.Revisions,
+		})
+	}
+}
+
+// GetRevision handles GET /admin/revisions/{kind}/{id}
+func (h *Handler) GetRevision(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		rev, err := h.service.GetRevision(r.Context(), kind, id)
+		if err != nil {
+			writeRevisionError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, rev)
+	}
+}
+
+// Diff handles GET /admin/revisions/{kind}/{id}/diff?against=
+func (h *Handler) Diff(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		against, ok := optionalID(w, r, "against")
+		if !ok {
+			return
+		}
+		diff, err := h.service.Diff(r.Context(), kind, id, against)
+		if err != nil {
+			writeRevisionError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, diff)
+	}
+}
+
+// Rollback handles POST /admin/revisions/{kind}/{id}/rollback
+func (h *Handler) Rollback(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		if err := h.service.Rollback(r.Context(), kind, id); err != nil {
+			if middleware.WritePreconditionFailed(w, r, err) {
+				return
+			}
+			writeRevisionError(w, err)
+			return
+		}
+		logger.Info("Rolled back content", "kind", kind, "revision", id, "by", actor(r))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Live writes the live row revision {id} is a revision of, the current
+// representation a rollback answers 412 with
+func (h *Handler) Live(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		row, err := h.service.GetLive(r.Context(), kind, id)
+		if err != nil {
+			writeRevisionError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, row)
+	}
+}
+
+// RollbackTarget resolves revision {id} to the live row a rollback of it
+// writes, whose version guards the rollback
+func (h *Handler) RollbackTarget(kind Kind) func(r *http.Request) (uint, bool, error) {
+	return func(r *http.Request) (uint, bool, error) {
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			return 0, false, nil
+		}
+		rev, err := h.service.GetRevision(r.Context(), kind, uint(id))
+		if errors.Is(err, ErrRevisionNotFound) {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		return rev.EntityID, true, nil
+	}
+}
+
+func writeRevisionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrRevisionNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.WriteServerError(w, err)
+}
+
+func idParam(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid revision ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// optionalID reads an id from the query string; it is nil when absent
+func optionalID(w http.ResponseWriter, r *http.Request, name string) (*uint, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, true
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid "+name)
+		return nil, false
+	}
+	id := uint(n)
+	return &id, true
+}
+
+func actor(r *http.Request) string {
+	if claims := middleware.GetUserFromContext(r.Context()); claims != nil {
+		return claims.Sub
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package revision
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+)
+
+// Kind names a content type whose writes are recorded
+type Kind string
+
+const (
+	KindHero          Kind = "hero"
+	KindAbout         Kind = "about"
+	KindSkills        Kind = "skills"
+	KindCareers       Kind = "careers"
+	KindProjects      Kind = "projects"
+	KindProjectPage   Kind = "project_page"
+	KindTestimonyPage Kind = "testimony_page"
+)
+
+// Kinds lists every recorded kind
+var Kinds = []Kind{KindHero, KindAbout, KindSkills, KindCareers, KindProjects, KindProjectPage, KindTestimonyPage}
+
+var cacheTags = map[Kind]cache.Tag{
+	KindHero:          cache.TagHero,
+	KindAbout:         cache.TagAbout,
+	KindSkills:        cache.TagSkills,
+	KindCareers:       cache.TagCareers,
+	KindProjects:      cache.TagProjects,
+	KindProjectPage:   cache.TagProjectPage,
+	KindTestimonyPage: cache.TagTestimonyPage,
+}
+
+// CacheTag is the tag of the cached public responses that show content of
+// the kind
+func (k Kind) CacheTag() cache.Tag {
+	return cacheTags[k]
+}
+
+// Model returns an empty row of the model behind the kind
+func (k Kind) Model() interface{} {
+	if newModel, ok := kindModels[k]; ok {
+		return newModel()
+	}
+	return nil
+}
+
+type RevisionDto struct {
+	ID        uint                  `json:"id"`
+	Kind      Kind                  `json:"kind"`
+	EntityID  uint                  `json:"entity_id"`
+	Action    models.RevisionAction `json:"action"`
+	AuthorID  *uint                 `json:"author_id"`
+	CreatedAt time.Time             `json:"created_at"`
+	// Snapshot is only filled in when a single revision is requested
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+}
+
+type RevisionListDto struct {
+	Revisions []RevisionDto `json:"revisions"`
+}
+
+// FieldChangeDto is one field whose value differs between two revisions
+type FieldChangeDto struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// DiffDto compares revision To with revision From; From is null when To is
+// the first revision of its entity
+type DiffDto struct {
+	From    *uint            `json:"from"`
+	To      uint             `json:"to"`
+	Changes []FieldChangeDto `json:"changes"`
+}
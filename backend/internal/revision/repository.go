@@ -0,0 +1,220 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
+	"gorm.io/gorm"
+)
+
+// kindModels creates an empty row of the model behind each kind
+var kindModels = map[Kind]func() interface{}{
+	KindHero:          func() interface{} { return &models.HeroPage{} },
+	KindAbout:         func() interface{} { return &models.AboutPage{} },
+	KindSkills:        func() interface{} { return &models.TechnicalSkills{} },
+	KindCareers:       func() interface{} { return &models.CareerJourney{} },
+	KindProjects:      func() interface{} { return &models.Project{} },
+	KindProjectPage:   func() interface{} { return &models.ProjectPage{} },
+	KindTestimonyPage: func() interface{} { return &models.TestimonyPage{} },
+}
+
+// Record stores a revision of row, a pointer to a model whose primary key is
+// set, like a row that was just created or saved
+func Record(tx *gorm.DB, kind Kind, action models.RevisionAction, row interface{}) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(row); err != nil {
+		return err
+	}
+	pk, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(tx.Statement.Context, reflect.Indirect(reflect.ValueOf(row)))
+	if zero {
+		return nil
+	}
+	id, ok := pk.(uint)
+	if !ok {
+		return fmt.Errorf("revision: %T has a non-uint primary key", row)
+	}
+	return RecordID(tx, kind, action, id)
+}
+
+// RecordID stores a revision of the row of kind with the given id. The row is
+// read back unscoped so the snapshot is the full stored state, including soft
+// deletion. Call it inside the transaction of the write; the author comes from
+// the request context of tx. A row that no longer exists is not recorded.
+func RecordID(tx *gorm.DB, kind Kind, action models.RevisionAction, id uint) error {
+	newModel, ok := kindModels[kind]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+
+	db := tx.Session(&gorm.Session{NewDB: true})
+	current := newModel()
+	if err := db.Unscoped().First(current, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	snapshot, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	return db.Create(&models.Revision{
+		Kind:     string(kind),
+		EntityID: id,
+		Action:   action,
+		AuthorID: author(tx.Statement.Context),
+		Snapshot: string(snapshot),
+	}).Error
+}
+
+// RecordWrite records row after the write whose result is res. Writes that
+// failed return their error and writes that matched no live row, like an
+// update of a trashed item, are not recorded.
+func RecordWrite(res *gorm.DB, kind Kind, action models.RevisionAction, row interface{}) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil
+	}
+	return Record(res, kind, action, row)
+}
+
+// author is the signed-in user behind ctx; writes made outside a request,
+// like seeding, have none
+func author(ctx context.Context) *uint {
+	if ctx == nil {
+		return nil
+	}
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil
+	}
+	id, err := strconv.ParseUint(claims.Sub, 10, 64)
+	if err != nil {
+		return nil
+	}
+	uid := uint(id)
+	return &uid
+}
+
+type RevisionRepository interface {
+	GetRevisions(ctx context.Context, kind Kind, entityID *uint, limit int) ([]models.Revision, error)
+	GetRevision(ctx context.Context, kind Kind, id uint) (*models.Revision, error)
+	GetPreviousRevision(ctx context.Context, rev *models.Revision) (*models.Revision, error)
+	GetLatestRevision(ctx context.Context, kind Kind, entityID *uint) (*models.Revision, error)
+	GetLive(ctx context.Context, rev *models.Revision) (interface{}, error)
+	Rollback(ctx context.Context, rev *models.Revision) error
+}
+
+type GormRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRevisionRepository(db *gorm.DB) *GormRevisionRepository {
+	return &GormRevisionRepository{db: db}
+}
+
+// GetRevisions lists revisions of a kind, newest first, optionally for one entity
+func (r *GormRevisionRepository) GetRevisions(ctx context.Context, kind Kind, entityID *uint, limit int) ([]models.Revision, error) {
+	query := r.db.WithContext(ctx).
+		Select("id", "kind", "entity_id", "action", "author_id", "created_at").
+		Where("kind = ?", kind)
+	if entityID != nil {
+		query = query.Where("entity_id = ?", *entityID)
+	}
+
+	var revisions []models.Revision
+	err := query.Order("id DESC").Limit(limit).Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision returns a revision with its snapshot, or ErrRecordNotFound if
+// it does not exist or belongs to another kind
+func (r *GormRevisionRepository) GetRevision(ctx context.Context, kind Kind, id uint) (*models.Revision, error) {
+	var rev models.Revision
+	if err := r.db.WithContext(ctx).Where("id = ? AND kind = ?", id, kind).First(&rev).Error; err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// GetPreviousRevision returns the revision of the same entity recorded just
+// before rev, or nil if rev is the first
+func (r *GormRevisionRepository) GetPreviousRevision(ctx context.Context, rev *models.Revision) (*models.Revision, error) {
+	var prev models.Revision
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND entity_id = ? AND id < ?", rev.Kind, rev.EntityID, rev.ID).
+		Order("id DESC").
+		First(&prev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &prev, nil
+}
+
+// GetLatestRevision returns the newest revision of a kind, optionally for one
+// entity, or nil if there is none
+func (r *GormRevisionRepository) GetLatestRevision(ctx context.Context, kind Kind, entityID *uint) (*models.Revision, error) {
+	query := r.db.WithContext(ctx).Where("kind = ?", kind)
+	if entityID != nil {
+		query = query.Where("entity_id = ?", *entityID)
+	}
+
+	var rev models.Revision
+	err := query.Order("id DESC").First(&rev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// GetLive returns the live row rev is a revision of, or ErrRecordNotFound if
+// it was deleted
+func (r *GormRevisionRepository) GetLive(ctx context.Context, rev *models.Revision) (interface{}, error) {
+	row := Kind(rev.Kind).Model()
+	if row == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, rev.Kind)
+	}
+	if err := r.db.WithContext(ctx).Where("id = ?", rev.EntityID).First(row).Error; err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// Rollback writes the snapshot of rev back over its row, recreating the row
+// if it was purged, and records the result as a new revision
+func (r *GormRevisionRepository) Rollback(ctx context.Context, rev *models.Revision) error {
+	newModel, ok := kindModels[Kind(rev.Kind)]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownKind, rev.Kind)
+	}
+	row := newModel()
+	if err := json.Unmarshal([]byte(rev.Snapshot), row); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, newModel(), rev.EntityID); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Save(row).Error; err != nil {
+			return err
+		}
+		return Record(tx, Kind(rev.Kind), models.RevisionRollback, row)
+	})
+}
@@ -0,0 +1,171 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownKind      = errors.New("unknown content kind")
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// ignoredFields change on every write and would clutter each diff. The
+// capitalized ones are the unused fields of the gorm.Model the content models
+// embed.
+var ignoredFields = map[string]bool{
+	"id": true, "created_at": true, "updated_at": true,
+	"ID": true, "CreatedAt": true, "UpdatedAt": true,
+}
+
+type Service struct {
+	repo       RevisionRepository
+	invalidate cache.Invalidator
+}
+
+// NewService calls invalidate after a rollback changed content
+func NewService(repo RevisionRepository, invalidate cache.Invalidator) *Service {
+	return &Service{repo: repo, invalidate: invalidate}
+}
+
+// GetRevisions lists revisions without their snapshots, newest first
+func (s *Service) GetRevisions(ctx context.Context, kind Kind, entityID *uint, limit int) (*RevisionListDto, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	revisions, err := s.repo.GetRevisions(ctx, kind, entityID, limit)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]RevisionDto, 0, len(revisions))
+	for _, rev := range revisions {
+		items = append(items, toDto(&rev, false))
+	}
+	return &RevisionListDto{Revisions: items}, nil
+}
+
+// GetRevision returns one revision with its snapshot
+func (s *Service) GetRevision(ctx context.Context, kind Kind, id uint) (*RevisionDto, error) {
+	rev, err := s.getRevision(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	dto := toDto(rev, true)
+	return &dto, nil
+}
+
+// Diff compares revision id with against, or with the previous revision of
+// the same entity when against is nil
+func (s *Service) Diff(ctx context.Context, kind Kind, id uint, against *uint) (*DiffDto, error) {
+	to, err := s.getRevision(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var from *models.Revision
+	if against != nil {
+		from, err = s.getRevision(ctx, kind, *against)
+		if err != nil {
+			return nil, err
+		}
+	} else if from, err = s.repo.GetPreviousRevision(ctx, to); err != nil {
+		return nil, err
+	}
+
+	var before map[string]interface{}
+	diff := &DiffDto{To: to.ID, Changes: []FieldChangeDto{}}
+	if from != nil {
+		diff.From = &from.ID
+		if err := json.Unmarshal([]byte(from.Snapshot), &before); err != nil {
+			return nil, err
+		}
+	}
+	var after map[string]interface{}
+	if err := json.Unmarshal([]byte(to.Snapshot), &after); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	for field := range before {
+		if _, ok := after[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if ignoredFields[field] || reflect.DeepEqual(before[field], after[field]) {
+			continue
+		}
+		diff.Changes = append(diff.Changes, FieldChangeDto{Field: field, From: before[field], To: after[field]})
+	}
+	return diff, nil
+}
+
+// Rollback restores the content of revision id
+func (s *Service) Rollback(ctx context.Context, kind Kind, id uint) error {
+	rev, err := s.getRevision(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Rollback(ctx, rev); err != nil {
+		return err
+	}
+	s.invalidate(ctx, kind.CacheTag())
+	return nil
+}
+
+// GetLive returns the live row revision id is a revision of
+func (s *Service) GetLive(ctx context.Context, kind Kind, id uint) (interface{}, error) {
+	rev, err := s.getRevision(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.repo.GetLive(ctx, rev)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	return row, err
+}
+
+func (s *Service) getRevision(ctx context.Context, kind Kind, id uint) (*models.Revision, error) {
+	rev, err := s.repo.GetRevision(ctx, kind, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+func toDto(rev *models.Revision, withSnapshot bool) RevisionDto {
+	dto := RevisionDto{
+		ID:        rev.ID,
+		Kind:      Kind(rev.Kind),
+		EntityID:  rev.EntityID,
+		Action:    rev.Action,
+		AuthorID:  rev.AuthorID,
+		CreatedAt: rev.CreatedAt,
+	}
+	if withSnapshot {
+		dto.Snapshot = json.RawMessage(rev.Snapshot)
+	}
+	return dto
+}
@@ -0,0 +1,123 @@
+// Package seed loads portfolio content from fixture files. A fixture has the
+// same shape as an export archive and may be written in YAML or JSON.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/othersidedrl/portfolio/backend/internal/archive"
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+//go:embed fixtures/*.yaml
+var fixtures embed.FS
+
+const (
+	// Default holds the placeholder pages created on startup
+	Default = "default"
+	// Demo is a complete example portfolio for local development
+	Demo = "demo"
+)
+
+// Builtin returns one of the fixtures shipped with the binary
+func Builtin(name string) (*archive.ArchiveDto, error) {
+	data, err := fixtures.ReadFile("fixtures/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown builtin fixture %q", name)
+	}
+	return Parse(data)
+}
+
+// Load reads a fixture file from disk
+func Load(path string) (*archive.ArchiveDto, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixture, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// Parse decodes a YAML or JSON fixture. YAML is converted to JSON first so
+// the DTOs' json tags apply, and unknown fields are rejected to catch typos.
+// A missing version means the current archive version.
+func Parse(data []byte) (*archive.ArchiveDto, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture archive.ArchiveDto
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fixture); err != nil {
+		return nil, err
+	}
+	if fixture.Version == 0 {
+		fixture.Version = archive.ArchiveVersion
+	}
+	return &fixture, nil
+}
+
+// Apply upserts the fixture: pages are overwritten and items are updated or
+// created by natural key, so running it twice changes nothing. Unless dryRun,
+// every cache tag is invalidated afterwards.
+func Apply(ctx context.Context, db *gorm.DB, fixture *archive.ArchiveDto, dryRun bool, invalidate cache.Invalidator) (*archive.ImportResultDto, error) {
+	service := archive.NewService(archive.NewGormArchiveRepository(db), invalidate)
+	return service.Import(ctx, fixture, archive.ImportMerge, dryRun)
+}
+
+// EnsurePages creates the pages of the fixture that do not exist yet and
+// leaves saved pages and all items alone
+func EnsurePages(ctx context.Context, db *gorm.DB, fixture *archive.ArchiveDto) error {
+	missing := archive.ArchiveDto{Version: fixture.Version}
+	pages := []struct {
+		model   interface{}
+		present bool
+		take    func()
+	}{
+		{&models.HeroPage{}, fixture.Hero != nil, func() { missing.Hero = fixture.Hero }},
+		{&models.AboutPage{}, fixture.About != nil, func() { missing.About = fixture.About }},
+		{&models.TestimonyPage{}, fixture.TestimonyPage != nil, func() { missing.TestimonyPage = fixture.TestimonyPage }},
+		{&models.ProjectPage{}, fixture.ProjectPage != nil, func() { missing.ProjectPage = fixture.ProjectPage }},
+	}
+	for _, p := range pages {
+		if !p.present {
+			continue
+		}
+		var count int64
+		if err := db.WithContext(ctx).Model(p.model).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			p.take()
+		}
+	}
+
+	// Runs at boot, before any response is cached here; a 404 cached in
+	// Redis for a missing page expires within the negative TTL
+	result, err := Apply(ctx, db, &missing, false, cache.Nop)
+	if err != nil {
+		return err
+	}
+	for _, page := range result.Pages {
+		logger.Info("Seeded page", "page", page)
+	}
+	return nil
+}
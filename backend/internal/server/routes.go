@@ -9,15 +9,28 @@ import (
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/othersidedrl/portfolio/backend/internal/about"
+	"github.com/othersidedrl/portfolio/backend/internal/apikey"
+	"github.com/othersidedrl/portfolio/backend/internal/archive"
 	"github.com/othersidedrl/portfolio/backend/internal/auth"
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
 	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/draft"
 	"github.com/othersidedrl/portfolio/backend/internal/health"
 	"github.com/othersidedrl/portfolio/backend/internal/hero"
 	"github.com/othersidedrl/portfolio/backend/internal/image"
+	"github.com/othersidedrl/portfolio/backend/internal/lockout"
 	customMiddleware "github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/oidc"
 	"github.com/othersidedrl/portfolio/backend/internal/project"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/session"
 	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"github.com/othersidedrl/portfolio/backend/internal/trash"
+	"github.com/othersidedrl/portfolio/backend/internal/user"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
 )
 
 func NewRouter(
@@ -28,7 +41,21 @@ func NewRouter(
 	testimonyHandler *testimony.Handler,
 	projectHandler *project.Handler,
 	imageHandler *image.Handler,
+	userHandler *user.Handler,
+	apiKeyHandler *apikey.Handler,
+	lockoutHandler *lockout.Handler,
+	sessionHandler *session.Handler,
+	oidcHandler *oidc.Handler,
+	trashHandler *trash.Handler,
+	archiveHandler *archive.Handler,
+	revisionHandler *revision.Handler,
+	draftHandler *draft.Handler,
 	jwtService *utils.JWTService,
+	tokenStore token.Store,
+	apiKeyService *apikey.Service,
+	sessionService *session.Service,
+	versions *version.Store,
+	cacheStore cache.Store,
 ) http.Handler {
 	r := chi.NewRouter()
 
@@ -37,6 +64,7 @@ func NewRouter(
 		allowedOrigins = []string{"http://localhost:3000"}
 	}
 
+	r.Use(customMiddleware.TrustedProxies(cfg.TrustedProxies))
 	r.Use(customMiddleware.SecurityHeaders)
 	r.Use(customMiddleware.RequestSizeLimit(10 << 20)) // 10MB limit
 	r.Use(customMiddleware.SanitizeInput)
@@ -49,8 +77,8 @@ func NewRouter(
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "If-Modified-Since", "If-None-Match", "X-CSRF-Token", "X-Requested-With"},
+		ExposedHeaders:   []string{"ETag", "Link", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300, // 5 mins
 	}))
@@ -64,16 +92,49 @@ func NewRouter(
 	// Content type validation for API routes
 	r.Use(customMiddleware.ValidateContentType)
 
-	// Auth middleware
-	authGuard := customMiddleware.AuthGuard(jwtService)
+	// Auth middleware: interactive sessions only under /auth, API keys also under /admin.
+	// Session cookies, when enabled, are accepted everywhere, guarded by CSRFProtect.
+	authGuard := customMiddleware.AuthGuard(customMiddleware.AuthGuardConfig{
+		JWT:      jwtService,
+		Denylist: tokenStore,
+		Sessions: sessionService,
+		Cookies:  cfg.CookieAuth,
+	})
+	adminGuard := customMiddleware.AuthGuard(customMiddleware.AuthGuardConfig{
+		JWT:      jwtService,
+		Denylist: tokenStore,
+		Sessions: sessionService,
+		APIKeys:  apiKeyService,
+		Cookies:  cfg.CookieAuth,
+	})
+	csrf := customMiddleware.CSRFProtect
+	requireScope := customMiddleware.RequireScope
 
-	// Redis
-	redis := utils.RedisClient
 	// Cache TTLs
-	// pageTTL := time.Hour
-	pageTTL := time.Second * 1
-	// sectionTTL := 30 * time.Minute
-	sectionTTL := time.Second * 1
+	pageTTL := customMiddleware.CacheTTL{
+		Fresh:    cfg.CachePageTTL,
+		Stale:    cfg.CacheStaleTTL,
+		Negative: cfg.CacheNegativeTTL,
+	}
+	sectionTTL := customMiddleware.CacheTTL{
+		Fresh:    cfg.CacheSectionTTL,
+		Stale:    cfg.CacheStaleTTL,
+		Negative: cfg.CacheNegativeTTL,
+	}
+
+	// Cache keys of the public lists. A handler that starts reading a query
+	// parameter or negotiating on a header must list it here. Responses are
+	// compressed outside the cache, so Accept-Encoding is never part of a key.
+	skillsKey := customMiddleware.CacheKey{Query: []string{"category"}}
+	listKey := customMiddleware.CacheKey{}
+	// Lets browsers, CDNs and the web frontend revalidate with a 304
+	publicCaching := customMiddleware.HTTPCaching{
+		MaxAge:               cfg.PublicMaxAge,
+		StaleWhileRevalidate: cfg.PublicStaleWhileRevalidate,
+	}
+
+	// Public keys for verifying access tokens
+	r.Get("/.well-known/jwks.json", authHandler.JWKS)
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/health", health.Health)
@@ -84,22 +145,25 @@ func NewRouter(
 			r.Use(publicRateLimiter.Handler)
 
 			// Hero Section (public - static content, simple cache key)
-			r.Get("/hero", customMiddleware.RedisCache(redis, "hero_page_cache", pageTTL, heroHandler.GetHeroPage))
+			r.Get("/hero", customMiddleware.Cache(cacheStore, "hero_page_cache", []cache.Tag{cache.TagHero}, pageTTL, publicCaching, heroHandler.GetHeroPage))
 
 			// About Section (public - static content, simple cache key)
-			r.Get("/about", customMiddleware.RedisCache(redis, "about_page_cache", pageTTL, aboutHandler.GetAboutPage))
-			r.Get("/about/skills", customMiddleware.RedisCacheWithParams(redis, "about_skills_cache", sectionTTL, aboutHandler.GetTechnicalSkills))
-			r.Get("/about/careers", customMiddleware.RedisCacheWithParams(redis, "about_careers_cache", sectionTTL, aboutHandler.GetCareers))
+			r.Get("/about", customMiddleware.Cache(cacheStore, "about_page_cache", []cache.Tag{cache.TagAbout}, pageTTL, publicCaching, aboutHandler.GetAboutPage))
+			r.Get("/about/skills", customMiddleware.CacheWithParams(cacheStore, "about_skills_cache", skillsKey, []cache.Tag{cache.TagSkills}, sectionTTL, publicCaching, aboutHandler.GetTechnicalSkills))
+			r.Get("/about/careers", customMiddleware.CacheWithParams(cacheStore, "about_careers_cache", listKey, []cache.Tag{cache.TagCareers}, sectionTTL, publicCaching, aboutHandler.GetCareers))
 
 			// Testimonies (public - static content, simple cache key)
-			r.Get("/testimony", customMiddleware.RedisCache(redis, "testimony_page_cache", pageTTL, testimonyHandler.GetTestimonyPage))
+			r.Get("/testimony", customMiddleware.Cache(cacheStore, "testimony_page_cache", []cache.Tag{cache.TagTestimonyPage}, pageTTL, publicCaching, testimonyHandler.GetTestimonyPage))
 			r.Post("/image", imageHandler.UploadProfileImage)
-			r.Post("/testimony/items", customMiddleware.RemoveCacheWithParams(redis, "testimony_approved_cache", testimonyHandler.CreateTestimony))
-			r.Get("/testimony/items/approved", customMiddleware.RedisCacheWithParams(redis, "testimony_approved_cache", sectionTTL, testimonyHandler.GetApprovedTestimonies))
+			r.Post("/testimony/items", testimonyHandler.CreateTestimony)
+			r.Get("/testimony/items/approved", customMiddleware.CacheWithParams(cacheStore, "testimony_approved_cache", listKey, []cache.Tag{cache.TagTestimoniesApproved}, sectionTTL, publicCaching, testimonyHandler.GetApprovedTestimonies))
+
+			// Projects (public - list keyed by its query parameters)
+			r.Get("/project", customMiddleware.Cache(cacheStore, "project_page_cache", []cache.Tag{cache.TagProjectPage}, pageTTL, publicCaching, projectHandler.GetProjectPage))
+			r.Get("/project/items", customMiddleware.CacheWithParams(cacheStore, "project_items_cache", listKey, []cache.Tag{cache.TagProjects}, sectionTTL, publicCaching, projectHandler.GetProjects))
 
-			// Projects (public - may have category filter, use dynamic cache)
-			r.Get("/project", customMiddleware.RedisCache(redis, "project_page_cache", pageTTL, projectHandler.GetProjectPage))
-			r.Get("/project/items", customMiddleware.RedisCacheWithParams(redis, "project_items_cache", sectionTTL, projectHandler.GetProjects))
+			// Draft preview (public - the token grants access, never cached)
+			r.With(customMiddleware.NoCache).Get("/preview", draftHandler.Preview)
 		})
 
 		// Auth
@@ -108,69 +172,234 @@ func NewRouter(
 			r.Use(authRateLimiter.Handler)
 
 			r.Post("/login", authHandler.Login)
+			r.Post("/login/verify", authHandler.VerifyLogin)
+
+			// External identity providers
+			r.Route("/oidc", func(r chi.Router) {
+				r.Get("/", oidcHandler.GetProviders)
+				r.Post("/exchange", authHandler.ExchangeTicket)
+				r.Get("/{provider}/login", oidcHandler.Login)
+				r.Get("/{provider}/callback", oidcHandler.Callback)
+			})
+			r.With(csrf).Post("/refresh", authHandler.Refresh)
+			r.With(authGuard, csrf).Post("/logout", authHandler.Logout)
 			r.With(authGuard).Get("/me", authHandler.Me)
+
+			// Signed-in devices of the current user
+			r.Route("/sessions", func(r chi.Router) {
+				r.Use(authGuard)
+				r.Use(csrf)
+
+				r.Get("/", sessionHandler.GetSessions)
+				r.Delete("/{id}", sessionHandler.RevokeSession)
+			})
+
+			// Two-factor enrollment for the signed-in user
+			r.Route("/totp", func(r chi.Router) {
+				r.Use(authGuard)
+				r.Use(csrf)
+
+				r.Post("/setup", userHandler.SetupTOTP)
+				r.Post("/enable", userHandler.EnableTOTP)
+				r.Post("/disable", userHandler.DisableTOTP)
+				r.Post("/recovery-codes", userHandler.RegenerateRecoveryCodes)
+			})
 		})
 
 		// Admin
 		r.Route("/admin", func(r chi.Router) {
-			r.Use(authGuard)
+			r.Use(adminGuard)
+			r.Use(csrf)
 			r.Use(customMiddleware.NoCache) // Prevent caching of admin data
 
+			// API keys (admin, any signed-in user manages their own)
+			r.Route("/api-keys", func(r chi.Router) {
+				r.Get("/", customMiddleware.WithETag(versions.List(&models.APIKey{}), apiKeyHandler.GetAPIKeys))
+				r.Post("/", apiKeyHandler.CreateAPIKey)
+				r.Delete("/{id}", apiKeyHandler.RevokeAPIKey)
+			})
+
+			// Users (admin)
+			r.Route("/users", func(r chi.Router) {
+				r.Use(requireScope(models.ScopeUsersManage))
+
+				userVersion := versions.Item(&models.User{})
+				r.Get("/", customMiddleware.WithETag(versions.List(&models.User{}), userHandler.GetUsers))
+				r.Post("/", userHandler.CreateUser)
+				r.Get("/{id}", customMiddleware.WithETag(userVersion, userHandler.GetUser))
+				r.Patch("/{id}", customMiddleware.IfMatch(userVersion, userHandler.GetUser, userHandler.UpdateUser))
+				r.Delete("/{id}", customMiddleware.IfMatch(userVersion, userHandler.GetUser, userHandler.DeleteUser))
+			})
+
+			// Login lockouts (admin)
+			r.Route("/lockouts", func(r chi.Router) {
+				r.Use(requireScope(models.ScopeUsersManage))
+
+				r.Get("/", lockoutHandler.GetLockouts)
+				r.Get("/{email}", lockoutHandler.GetLockout)
+				r.Delete("/{email}", lockoutHandler.ClearLockout)
+			})
+
 			// Hero Section (admin)
 			r.Route("/hero", func(r chi.Router) {
-				r.Get("/", heroHandler.GetHeroPage)
+				r.Use(requireScope(models.ScopeHeroWrite))
+
+				heroVersion := versions.Page(&models.HeroPage{})
+				r.Get("/", customMiddleware.WithETag(heroVersion, heroHandler.GetHeroPage))
 				r.Post("/image", imageHandler.UploadHeroImage)
-				r.Patch("/", customMiddleware.RemoveCache(redis, "hero_page_cache", heroHandler.UpdateHeroPage))
+				r.Patch("/", customMiddleware.IfMatch(heroVersion, heroHandler.GetHeroPage, heroHandler.UpdateHeroPage))
 			})
 
 			// About Section (admin)
 			r.Route("/about", func(r chi.Router) {
-				r.Get("/", aboutHandler.GetAboutPage)
-				r.Patch("/", customMiddleware.RemoveCache(redis, "about_page_cache", aboutHandler.UpdateAboutPage))
+				r.Use(requireScope(models.ScopeAboutWrite))
+
+				aboutVersion := versions.Page(&models.AboutPage{})
+				r.Get("/", customMiddleware.WithETag(aboutVersion, aboutHandler.GetAboutPage))
+				r.Patch("/", customMiddleware.IfMatch(aboutVersion, aboutHandler.GetAboutPage, aboutHandler.UpdateAboutPage))
 
 				// About Skills (admin)
 				r.Route("/skills", func(r chi.Router) {
-					r.Get("/", aboutHandler.GetTechnicalSkills)
-					r.Post("/", customMiddleware.RemoveCache(redis, "about_skills_cache", aboutHandler.CreateTechnicalSkill))
-					r.Patch("/{id}", customMiddleware.RemoveCache(redis, "about_skills_cache", aboutHandler.UpdateTechnicalSkill))
-					r.Delete("/{id}", customMiddleware.RemoveCache(redis, "about_skills_cache", aboutHandler.DeleteTechnicalSkill))
+					skillVersion := versions.Item(&models.TechnicalSkills{})
+					r.Get("/", customMiddleware.WithETag(versions.List(&models.TechnicalSkills{}), aboutHandler.GetTechnicalSkills))
+					r.Get("/{id}", customMiddleware.WithETag(skillVersion, aboutHandler.GetTechnicalSkill))
+					r.Post("/", aboutHandler.CreateTechnicalSkill)
+					r.Patch("/{id}", customMiddleware.IfMatch(skillVersion, aboutHandler.GetTechnicalSkill, aboutHandler.UpdateTechnicalSkill))
+					r.Delete("/{id}", customMiddleware.IfMatch(skillVersion, aboutHandler.GetTechnicalSkill, aboutHandler.DeleteTechnicalSkill))
 				})
 
 				// About Careers (admin)
 				r.Route("/careers", func(r chi.Router) {
-					r.Get("/", aboutHandler.GetCareers)
-					r.Post("/", customMiddleware.RemoveCache(redis, "about_careers_cache", aboutHandler.CreateCareer))
-					r.Patch("/{id}", customMiddleware.RemoveCache(redis, "about_careers_cache", aboutHandler.UpdateCareer))
-					r.Delete("/{id}", customMiddleware.RemoveCache(redis, "about_careers_cache", aboutHandler.DeleteCareer))
+					careerVersion := versions.Item(&models.CareerJourney{})
+					r.Get("/", customMiddleware.WithETag(versions.List(&models.CareerJourney{}), aboutHandler.GetCareers))
+					r.Get("/{id}", customMiddleware.WithETag(careerVersion, aboutHandler.GetCareer))
+					r.Post("/", aboutHandler.CreateCareer)
+					r.Patch("/{id}", customMiddleware.IfMatch(careerVersion, aboutHandler.GetCareer, aboutHandler.UpdateCareer))
+					r.Delete("/{id}", customMiddleware.IfMatch(careerVersion, aboutHandler.GetCareer, aboutHandler.DeleteCareer))
 				})
 			})
 
 			// Testimonies (admin)
 			r.Route("/testimony", func(r chi.Router) {
-				r.Get("/", testimonyHandler.GetTestimonyPage)
-				r.Patch("/", customMiddleware.RemoveCache(redis, "testimony_page_cache", testimonyHandler.UpdateTestimonyPage))
+				testimonyPageVersion := versions.Page(&models.TestimonyPage{})
+				r.With(requireScope(models.ScopeTestimonyWrite, models.ScopeTestimonyModerate)).Get("/",
+					customMiddleware.WithETag(testimonyPageVersion, testimonyHandler.GetTestimonyPage))
+				r.With(requireScope(models.ScopeTestimonyWrite)).Patch("/", customMiddleware.IfMatch(testimonyPageVersion, testimonyHandler.GetTestimonyPage, testimonyHandler.UpdateTestimonyPage))
 
+				// Moderators can review testimonies but not edit page copy
 				r.Route("/items", func(r chi.Router) {
-					r.Get("/", testimonyHandler.GetTestimonies)
-					r.Patch("/{id}", customMiddleware.RemoveCacheWithParams(redis, "testimony_approved_cache", testimonyHandler.UpdateTestimony))
-					r.Patch("/{id}/approve", customMiddleware.RemoveCacheWithParams(redis, "testimony_approved_cache", testimonyHandler.ApproveTestimony))
-					r.Delete("/{id}", customMiddleware.RemoveCacheWithParams(redis, "testimony_approved_cache", testimonyHandler.DeleteTestimony))
+					r.Use(requireScope(models.ScopeTestimonyModerate))
+
+					testimonyVersion := versions.Item(&models.Testimony{})
+					r.Get("/", customMiddleware.WithETag(versions.List(&models.Testimony{}), testimonyHandler.GetTestimonies))
+					r.Get("/{id}", customMiddleware.WithETag(testimonyVersion, testimonyHandler.GetTestimony))
+					r.Patch("/{id}", customMiddleware.IfMatch(testimonyVersion, testimonyHandler.GetTestimony, testimonyHandler.UpdateTestimony))
+					r.Patch("/{id}/approve", customMiddleware.IfMatch(testimonyVersion, testimonyHandler.GetTestimony, testimonyHandler.ApproveTestimony))
+					r.Delete("/{id}", customMiddleware.IfMatch(testimonyVersion, testimonyHandler.GetTestimony, testimonyHandler.DeleteTestimony))
 				})
 			})
 
 			// Projects (admin)
 			r.Route("/project", func(r chi.Router) {
-				r.Get("/", projectHandler.GetProjectPage)
-				r.Patch("/", customMiddleware.RemoveCache(redis, "project_page_cache", projectHandler.UpdateProjectPage))
+				r.Use(requireScope(models.ScopeProjectWrite))
+
+				projectPageVersion := versions.Page(&models.ProjectPage{})
+				r.Get("/", customMiddleware.WithETag(projectPageVersion, projectHandler.GetProjectPage))
+				r.Patch("/", customMiddleware.IfMatch(projectPageVersion, projectHandler.GetProjectPage, projectHandler.UpdateProjectPage))
 
 				r.Route("/items", func(r chi.Router) {
-					r.Get("/", projectHandler.GetProjects)
+					projectVersion := versions.Item(&models.Project{})
+					r.Get("/", customMiddleware.WithETag(versions.List(&models.Project{}), projectHandler.GetProjects))
+					r.Get("/{id}", customMiddleware.WithETag(projectVersion, projectHandler.GetProject))
 					r.Post("/image", imageHandler.UploadProjectImage)
-					r.Post("/", customMiddleware.RemoveCacheWithParams(redis, "project_items_cache", projectHandler.CreateProject))
-					r.Patch("/{id}", customMiddleware.RemoveCacheWithParams(redis, "project_items_cache", projectHandler.UpdateProject))
-					r.Delete("/{id}", customMiddleware.RemoveCacheWithParams(redis, "project_items_cache", projectHandler.DeleteProject))
+					r.Post("/", projectHandler.CreateProject)
+					r.Patch("/{id}", customMiddleware.IfMatch(projectVersion, projectHandler.GetProject, projectHandler.UpdateProject))
+					r.Delete("/{id}", customMiddleware.IfMatch(projectVersion, projectHandler.GetProject, projectHandler.DeleteProject))
 				})
 			})
+
+			// Trash (admin), each kind needs the scope that deletes it
+			trashKinds := []struct {
+				kind  trash.Kind
+				scope models.Scope
+			}{
+				{trash.KindProjects, models.ScopeProjectWrite},
+				{trash.KindTestimonies, models.ScopeTestimonyModerate},
+				{trash.KindSkills, models.ScopeAboutWrite},
+				{trash.KindCareers, models.ScopeAboutWrite},
+			}
+			r.Route("/trash", func(r chi.Router) {
+				for _, t := range trashKinds {
+					r.Route("/"+string(t.kind), func(r chi.Router) {
+						r.Use(requireScope(t.scope))
+
+						r.Get("/", trashHandler.GetTrash(t.kind))
+						r.Post("/{id}/restore", trashHandler.Restore(t.kind))
+						r.Delete("/{id}", trashHandler.Purge(t.kind))
+					})
+				}
+			})
+
+			// Revisions (admin), each kind needs the scope that edits it
+			revisionKinds := []struct {
+				kind  revision.Kind
+				scope models.Scope
+			}{
+				{revision.KindHero, models.ScopeHeroWrite},
+				{revision.KindAbout, models.ScopeAboutWrite},
+				{revision.KindSkills, models.ScopeAboutWrite},
+				{revision.KindCareers, models.ScopeAboutWrite},
+				{revision.KindProjects, models.ScopeProjectWrite},
+				{revision.KindProjectPage, models.ScopeProjectWrite},
+				{revision.KindTestimonyPage, models.ScopeTestimonyWrite},
+			}
+			r.Route("/revisions", func(r chi.Router) {
+				for _, k := range revisionKinds {
+					r.Route("/"+string(k.kind), func(r chi.Router) {
+						r.Use(requireScope(k.scope))
+
+						// A rollback is guarded by the version of the live row it
+						// overwrites, not of the revision, which never changes
+						targetVersion := versions.Of(k.kind.Model(), revisionHandler.RollbackTarget(k.kind))
+						r.Get("/", customMiddleware.WithETag(versions.Entries(&models.Revision{}), revisionHandler.GetRevisions(k.kind)))
+						r.Get("/{id}", customMiddleware.WithETag(versions.Entry(&models.Revision{}), revisionHandler.GetRevision(k.kind)))
+						r.Get("/{id}/diff", revisionHandler.Diff(k.kind))
+						r.Post("/{id}/rollback", customMiddleware.IfMatch(targetVersion, revisionHandler.Live(k.kind), revisionHandler.Rollback(k.kind)))
+					})
+				}
+			})
+
+			// Drafts (admin), same scopes as revisions
+			r.Route("/drafts", func(r chi.Router) {
+				for _, k := range revisionKinds {
+					r.Route("/"+string(k.kind), func(r chi.Router) {
+						r.Use(requireScope(k.scope))
+
+						draftVersion := versions.Item(&models.Draft{})
+						r.Get("/", customMiddleware.WithETag(versions.List(&models.Draft{}), draftHandler.GetDrafts(k.kind)))
+						r.Post("/", draftHandler.Create(k.kind))
+						r.Get("/{id}", customMiddleware.WithETag(draftVersion, draftHandler.GetDraft(k.kind)))
+						r.Patch("/{id}", customMiddleware.IfMatch(draftVersion, draftHandler.GetDraft(k.kind), draftHandler.Update(k.kind)))
+						r.Delete("/{id}", customMiddleware.IfMatch(draftVersion, draftHandler.GetDraft(k.kind), draftHandler.Delete(k.kind)))
+						r.Post("/{id}/publish", customMiddleware.IfMatch(draftVersion, draftHandler.GetDraft(k.kind), draftHandler.Publish(k.kind)))
+						r.Post("/{id}/unpublish", customMiddleware.IfMatch(draftVersion, draftHandler.GetDraft(k.kind), draftHandler.Unpublish(k.kind)))
+						r.Post("/{id}/preview", draftHandler.PreviewToken(k.kind))
+					})
+				}
+			})
+
+			// Export / Import (admin), covers every section so it needs
+			// every content scope
+			r.Group(func(r chi.Router) {
+				r.Use(requireScope(models.ScopeHeroWrite))
+				r.Use(requireScope(models.ScopeAboutWrite))
+				r.Use(requireScope(models.ScopeProjectWrite))
+				r.Use(requireScope(models.ScopeTestimonyWrite))
+				r.Use(requireScope(models.ScopeTestimonyModerate))
+
+				r.Get("/export", archiveHandler.Export)
+				r.Post("/import", archiveHandler.Import)
+			})
 		})
 	})
 
@@ -0,0 +1,70 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetSessions handles GET /auth/sessions
+func (h *Handler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	current := middleware.GetUserFromContext(r.Context()).Family
+	sessions, err := h.service.GetSessions(r.Context(), userID, current)
+	if err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"length": len(sessions.Sessions),
+		"data":   sessions.Sessions,
+	})
+}
+
+// RevokeSession handles DELETE /auth/sessions/{id}
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeSession(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			utils.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.WriteServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func currentUserID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return 0, false
+	}
+	id, err := strconv.ParseUint(claims.Sub, 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,18 @@
+package session
+
+import "time"
+
+type SessionItemDto struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current marks the session the request was made with
+	Current bool `json:"current"`
+}
+
+type SessionDto struct {
+	Sessions []SessionItemDto `json:"sessions"`
+}
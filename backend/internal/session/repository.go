@@ -0,0 +1,83 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	GetActiveSessions(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	GetSessionByID(ctx context.Context, id string) (*models.Session, error)
+	CreateSession(ctx context.Context, session *models.Session) error
+	ExtendSession(ctx context.Context, id string, seenAt, expiresAt time.Time) error
+	TouchSession(ctx context.Context, id string, seenAt time.Time) error
+	RevokeSession(ctx context.Context, id string) error
+	DeleteExpiredSessions(ctx context.Context, userID uint, now time.Time) error
+}
+
+type GormSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSessionRepository(db *gorm.DB) *GormSessionRepository {
+	return &GormSessionRepository{db: db}
+}
+
+// GetActiveSessions lists a user's sessions that are neither revoked nor expired
+func (r *GormSessionRepository) GetActiveSessions(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *GormSessionRepository) GetSessionByID(ctx context.Context, id string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *GormSessionRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *GormSessionRepository) ExtendSession(ctx context.Context, id string, seenAt, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		UpdateColumns(map[string]interface{}{
+			"last_seen_at": seenAt,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+func (r *GormSessionRepository) TouchSession(ctx context.Context, id string, seenAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("id = ?", id).
+		UpdateColumn("last_seen_at", seenAt).Error
+}
+
+func (r *GormSessionRepository) RevokeSession(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// DeleteExpiredSessions removes a user's sessions that can no longer be used
+func (r *GormSessionRepository) DeleteExpiredSessions(ctx context.Context, userID uint, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND (expires_at <= ? OR revoked_at IS NOT NULL)", userID, now).
+		Delete(&models.Session{}).Error
+}
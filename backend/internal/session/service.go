@@ -0,0 +1,136 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"gorm.io/gorm"
+)
+
+// lastSeenResolution limits how often last_seen_at is written per session
+const lastSeenResolution = time.Minute
+
+// maxUserAgentLength keeps oversized headers out of the table
+const maxUserAgentLength = 512
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session revoked or expired")
+)
+
+type Service struct {
+	repo   SessionRepository
+	tokens token.Store
+	ttl    time.Duration
+}
+
+// NewService tracks sessions that live as long as their refresh tokens
+func NewService(repo SessionRepository, tokens token.Store, ttl time.Duration) *Service {
+	return &Service{
+		repo:   repo,
+		tokens: tokens,
+		ttl:    ttl,
+	}
+}
+
+// StartSession records a new sign-in for the token family id
+func (s *Service) StartSession(ctx context.Context, userID uint, id, userAgent, ip string) error {
+	now := time.Now()
+	if err := s.repo.DeleteExpiredSessions(ctx, userID, now); err != nil {
+		return err
+	}
+
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return s.repo.CreateSession(ctx, &models.Session{
+		ID:         id,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  ip,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.ttl),
+	})
+}
+
+// ExtendSession pushes back the expiry after the refresh token was rotated
+func (s *Service) ExtendSession(ctx context.Context, id string) error {
+	now := time.Now()
+	return s.repo.ExtendSession(ctx, id, now, now.Add(s.ttl))
+}
+
+// ValidateSession rejects sessions that were revoked, expired or never
+// recorded, and tracks when the session was last used. It satisfies
+// middleware.SessionValidator.
+func (s *Service) ValidateSession(ctx context.Context, id string) error {
+	session, err := s.repo.GetSessionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) > lastSeenResolution {
+		if err := s.repo.TouchSession(ctx, id, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSessions lists the user's active sessions; current is the session the
+// request was made with
+func (s *Service) GetSessions(ctx context.Context, userID uint, current string) (*SessionDto, error) {
+	sessions, err := s.repo.GetActiveSessions(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]SessionItemDto, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, SessionItemDto{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == current,
+		})
+	}
+	return &SessionDto{Sessions: items}, nil
+}
+
+// RevokeSession signs the user out of one of their own sessions, killing its
+// refresh tokens and every access token issued for it
+func (s *Service) RevokeSession(ctx context.Context, userID uint, id string) error {
+	session, err := s.repo.GetSessionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+
+	return s.EndSession(ctx, id)
+}
+
+// EndSession marks a session revoked and revokes its token family
+func (s *Service) EndSession(ctx context.Context, id string) error {
+	if err := s.tokens.RevokeFamily(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.RevokeSession(ctx, id)
+}
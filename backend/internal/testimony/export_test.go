@@ -0,0 +1,6 @@
+package testimony
+
+// SetSummaryURL points the AI summaries of s at url, e.g. a stub server
+func SetSummaryURL(s *Service, url string) {
+	s.summaryURL = url
+}
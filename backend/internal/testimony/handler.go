@@ -2,11 +2,14 @@ package testimony
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
 	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"gorm.io/gorm"
 )
 
 type Handler struct {
@@ -27,13 +30,23 @@ func (h *Handler) GetTestimonyPage(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(page)
 }
 
+// UpdateTestimonyPage applies a JSON merge patch to the testimony page
 func (h *Handler) UpdateTestimonyPage(w http.ResponseWriter, r *http.Request) {
-	var body TestimonyPageDto
-	if err := utils.DecodeBody(r, &body); err != nil {
+	body, err := h.service.GetTestimonyPage(r.Context())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		body = &TestimonyPageDto{}
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if err := h.service.UpdateTestimonyPage(r.Context(), &body); err != nil {
+	if err := h.service.UpdateTestimonyPage(r.Context(), body); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -60,6 +73,25 @@ func (h *Handler) GetTestimonies(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *Handler) GetTestimony(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid testimony ID", http.StatusBadRequest)
+		return
+	}
+	testimony, err := h.service.GetTestimonyByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Testimony not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(testimony)
+}
+
 func (h *Handler) GetApprovedTestimonies(w http.ResponseWriter, r *http.Request) {
 	testimonies, err := h.service.GetApprovedTestimonies(r.Context())
 	if err != nil {
@@ -93,18 +125,31 @@ func (h *Handler) CreateTestimony(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Successfully created a testimony"})
 }
 
+// UpdateTestimony applies a JSON merge patch to a testimony, so a rating of
+// 0 or approved set to false are written like any other value
 func (h *Handler) UpdateTestimony(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "Invalid testimony ID", http.StatusBadRequest)
 		return
 	}
-	var body TestimonyItemDto
-	if err := utils.DecodeBody(r, &body); err != nil {
+	body, err := h.service.GetTestimonyByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Testimony not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := utils.PatchBody(r, body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if err := h.service.UpdateTestimony(r.Context(), &body, uint(id)); err != nil {
+	if err := h.service.UpdateTestimony(r.Context(), body, uint(id)); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -119,12 +164,24 @@ func (h *Handler) ApproveTestimony(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid testimony ID", http.StatusBadRequest)
 		return
 	}
-	var body ApproveTestimonyDto
-	if err := utils.DecodeBody(r, &body); err != nil {
+	existing, err := h.service.GetTestimonyByID(r.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Testimony not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body := ApproveTestimonyDto{Approved: existing.Approved}
+	if err := utils.PatchBody(r, &body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	if err := h.service.ApproveTestimony(r.Context(), &body, uint(id)); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -139,6 +196,9 @@ func (h *Handler) DeleteTestimony(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.service.DeleteTestimony(r.Context(), uint(id)); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,127 @@
+package testimony
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// MemoryTestimonyRepository is an in-memory implementation of
+// TestimonyRepository for tests and database-free runs. It is safe for
+// concurrent use.
+type MemoryTestimonyRepository struct {
+	mu          sync.RWMutex
+	page        *TestimonyPageDto
+	testimonies map[uint]TestimonyItemDto
+	nextID      uint
+}
+
+// NewMemoryTestimonyRepository creates an empty MemoryTestimonyRepository
+func NewMemoryTestimonyRepository() *MemoryTestimonyRepository {
+	return &MemoryTestimonyRepository{
+		testimonies: map[uint]TestimonyItemDto{},
+		nextID:      1,
+	}
+}
+
+func (r *MemoryTestimonyRepository) GetTestimonyPage(ctx context.Context) (*TestimonyPageDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.page == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	page := *r.page
+	return &page, nil
+}
+
+func (r *MemoryTestimonyRepository) UpdateTestimonyPage(ctx context.Context, data *TestimonyPageDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	page := *data
+	r.page = &page
+	return nil
+}
+
+func (r *MemoryTestimonyRepository) GetTestimonies(ctx context.Context) (*TestimonyDto, error) {
+	return r.list(func(TestimonyItemDto) bool { return true }), nil
+}
+
+func (r *MemoryTestimonyRepository) GetApprovedTestimonies(ctx context.Context) (*TestimonyDto, error) {
+	return r.list(func(t TestimonyItemDto) bool { return t.Approved }), nil
+}
+
+// CreateTestimony always stores new testimonies unapproved
+func (r *MemoryTestimonyRepository) CreateTestimony(ctx context.Context, data *TestimonyItemDto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	testimony := *data
+	testimony.ID = int(r.nextID)
+	testimony.Approved = false
+	r.testimonies[r.nextID] = testimony
+	r.nextID++
+	return nil
+}
+
+// UpdateTestimony overwrites every field of the testimony, including empty
+// ones, so it can also unapprove. Unknown ids are ignored.
+func (r *MemoryTestimonyRepository) UpdateTestimony(ctx context.Context, data *TestimonyItemDto, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.testimonies[id]; !ok {
+		return nil
+	}
+	testimony := *data
+	testimony.ID = int(id)
+	r.testimonies[id] = testimony
+	return nil
+}
+
+func (r *MemoryTestimonyRepository) ApproveTestimony(ctx context.Context, data *ApproveTestimonyDto, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if testimony, ok := r.testimonies[id]; ok {
+		testimony.Approved = data.Approved
+		r.testimonies[id] = testimony
+	}
+	return nil
+}
+
+func (r *MemoryTestimonyRepository) DeleteTestimony(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.testimonies, id)
+	return nil
+}
+
+func (r *MemoryTestimonyRepository) GetTestimonyByID(ctx context.Context, id uint) (*TestimonyItemDto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	testimony, ok := r.testimonies[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &testimony, nil
+}
+
+func (r *MemoryTestimonyRepository) list(keep func(TestimonyItemDto) bool) *TestimonyDto {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var testimonies []TestimonyItemDto
+	for _, id := range slices.Sorted(maps.Keys(r.testimonies)) {
+		if t := r.testimonies[id]; keep(t) {
+			testimonies = append(testimonies, t)
+		}
+	}
+	return &TestimonyDto{Testimonies: testimonies}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
 	"gorm.io/gorm"
 )
 
@@ -40,19 +42,25 @@ func (r *GormTestimonyRepository) GetTestimonyPage(ctx context.Context) (*Testim
 }
 
 func (r *GormTestimonyRepository) UpdateTestimonyPage(ctx context.Context, data *TestimonyPageDto) error {
-	var page models.TestimonyPage
-	if err := r.db.WithContext(ctx).First(&page).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return r.db.WithContext(ctx).Create(&models.TestimonyPage{
-				Title:       data.Title,
-				Description: data.Description,
-			}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockPage(ctx, tx, &models.TestimonyPage{}); err != nil {
+			return err
 		}
-		return err
-	}
-	page.Title = data.Title
-	page.Description = data.Description
-	return r.db.WithContext(ctx).Save(&page).Error
+		var page models.TestimonyPage
+		if err := tx.First(&page).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				page = models.TestimonyPage{
+					Title:       data.Title,
+					Description: data.Description,
+				}
+				return revision.RecordWrite(tx.Create(&page), revision.KindTestimonyPage, models.RevisionCreate, &page)
+			}
+			return err
+		}
+		page.Title = data.Title
+		page.Description = data.Description
+		return revision.RecordWrite(tx.Save(&page), revision.KindTestimonyPage, models.RevisionUpdate, &page)
+	})
 }
 
 func (r *GormTestimonyRepository) GetTestimonies(ctx context.Context) (*TestimonyDto, error) {
@@ -110,24 +118,43 @@ func (r *GormTestimonyRepository) CreateTestimony(ctx context.Context, data *Tes
 	return r.db.WithContext(ctx).Create(&testimony).Error
 }
 
+// UpdateTestimony overwrites every field of the testimony, including empty
+// ones like a rating of 0 or approved set to false
 func (r *GormTestimonyRepository) UpdateTestimony(ctx context.Context, data *TestimonyItemDto, id uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Updates(&models.Testimony{
-		Name:        data.Name,
-		ProfileUrl:  data.ProfileUrl,
-		Affiliation: data.Affiliation,
-		Rating:      data.Rating,
-		Description: data.Description,
-		AISummary:   data.AISummary,
-		Approved:    data.Approved,
-	}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Testimony{}, id); err != nil {
+			return err
+		}
+		return tx.Model(&models.Testimony{}).Where("id = ?", id).
+			Select("*").Omit("id", "created_at", "deleted_at").
+			Updates(&models.Testimony{
+				Name:        data.Name,
+				ProfileUrl:  data.ProfileUrl,
+				Affiliation: data.Affiliation,
+				Rating:      data.Rating,
+				Description: data.Description,
+				AISummary:   data.AISummary,
+				Approved:    data.Approved,
+			}).Error
+	})
 }
 
 func (r *GormTestimonyRepository) ApproveTestimony(ctx context.Context, data *ApproveTestimonyDto, id uint) error {
-	return r.db.WithContext(ctx).Model(&models.Testimony{}).Where("id = ?", id).Update("approved", data.Approved).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Testimony{}, id); err != nil {
+			return err
+		}
+		return tx.Model(&models.Testimony{}).Where("id = ?", id).Update("approved", data.Approved).Error
+	})
 }
 
 func (r *GormTestimonyRepository) DeleteTestimony(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Unscoped().Delete(&models.Testimony{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.Testimony{}, id); err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.Testimony{}).Error
+	})
 }
 
 func (r *GormTestimonyRepository) GetTestimonyByID(ctx context.Context, id uint) (*TestimonyItemDto, error) {
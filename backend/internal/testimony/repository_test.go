@@ -0,0 +1,20 @@
+package testimony_test
+
+import (
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+)
+
+func TestMemoryTestimonyRepository(t *testing.T) {
+	repotest.RunTestimonyRepository(t, func(t *testing.T) testimony.TestimonyRepository {
+		return testimony.NewMemoryTestimonyRepository()
+	})
+}
+
+func TestGormTestimonyRepository(t *testing.T) {
+	repotest.RunTestimonyRepository(t, func(t *testing.T) testimony.TestimonyRepository {
+		return testimony.NewGormTestimonyRepository(repotest.NewSQLiteDB(t))
+	})
+}
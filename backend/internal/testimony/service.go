@@ -12,16 +12,25 @@ import (
 
 	"strings"
 
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
 	"github.com/othersidedrl/portfolio/backend/internal/config"
 )
 
+// summaryURL is the OpenRouter endpoint that writes the AI summaries
+const summaryURL = "https://openrouter.ai/api/v1/chat/completions"
+
 type Service struct {
-	repo TestimonyRepository
-	cfg  *config.Config
+	repo       TestimonyRepository
+	cfg        *config.Config
+	invalidate cache.Invalidator
+	summaryURL string
 }
 
-func NewService(repo TestimonyRepository, cfg *config.Config) *Service {
-	return &Service{repo, cfg}
+// NewService calls invalidate after the testimony page or a testimony
+// changed. New testimonies wait for approval, so creating one changes no
+// public content.
+func NewService(repo TestimonyRepository, cfg *config.Config, invalidate cache.Invalidator) *Service {
+	return &Service{repo, cfg, invalidate, summaryURL}
 }
 
 func (s *Service) GetTestimonyPage(ctx context.Context) (*TestimonyPageDto, error) {
@@ -29,7 +38,11 @@ func (s *Service) GetTestimonyPage(ctx context.Context) (*TestimonyPageDto, erro
 }
 
 func (s *Service) UpdateTestimonyPage(ctx context.Context, data *TestimonyPageDto) error {
-	return s.repo.UpdateTestimonyPage(ctx, data)
+	if err := s.repo.UpdateTestimonyPage(ctx, data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagTestimonyPage)
+	return nil
 }
 
 func (s *Service) GetTestimonies(ctx context.Context) (*TestimonyDto, error) {
@@ -40,6 +53,10 @@ func (s *Service) GetApprovedTestimonies(ctx context.Context) (*TestimonyDto, er
 	return s.repo.GetApprovedTestimonies(ctx)
 }
 
+func (s *Service) GetTestimonyByID(ctx context.Context, id uint) (*TestimonyItemDto, error) {
+	return s.repo.GetTestimonyByID(ctx, id)
+}
+
 func (s *Service) CreateTestimony(ctx context.Context, data *TestimonyItemDto) error {
 	// Step 0: Set default avatar if missing
 	if data.ProfileUrl == "" {
@@ -53,7 +70,11 @@ func (s *Service) CreateTestimony(ctx context.Context, data *TestimonyItemDto) e
 }
 
 func (s *Service) UpdateTestimony(ctx context.Context, data *TestimonyItemDto, id uint) error {
-	return s.repo.UpdateTestimony(ctx, data, id)
+	if err := s.repo.UpdateTestimony(ctx, data, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagTestimoniesApproved)
+	return nil
 }
 
 func (s *Service) ApproveTestimony(ctx context.Context, data *ApproveTestimonyDto, id uint) error {
@@ -76,10 +97,15 @@ func (s *Service) ApproveTestimony(ctx context.Context, data *ApproveTestimonyDt
 
 		existing.Approved = true
 		// We use UpdateTestimony to save the summary + approval status
-		return s.repo.UpdateTestimony(ctx, existing, id)
+		if err := s.repo.UpdateTestimony(ctx, existing, id); err != nil {
+			return err
+		}
+	} else if err := s.repo.ApproveTestimony(ctx, data, id); err != nil {
+		return err
 	}
 
-	return s.repo.ApproveTestimony(ctx, data, id)
+	s.invalidate(ctx, cache.TagTestimoniesApproved)
+	return nil
 }
 
 func (s *Service) generateAISummary(ctx context.Context, description string) (string, error) {
@@ -112,7 +138,7 @@ func (s *Service) generateAISummary(ctx context.Context, description string) (st
 		return "", err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://openrouter.ai/api/v1/chat/completions", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", s.summaryURL, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return "", err
 	}
@@ -162,5 +188,9 @@ func (s *Service) generateAISummary(ctx context.Context, description string) (st
 }
 
 func (s *Service) DeleteTestimony(ctx context.Context, id uint) error {
-	return s.repo.DeleteTestimony(ctx, id)
+	if err := s.repo.DeleteTestimony(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, cache.TagTestimoniesApproved)
+	return nil
 }
@@ -0,0 +1,165 @@
+package testimony_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/config"
+	"github.com/othersidedrl/portfolio/backend/internal/repotest"
+	"github.com/othersidedrl/portfolio/backend/internal/testimony"
+)
+
+// newTestService returns a service on an in-memory repository whose AI
+// summaries come from a stub OpenRouter answering summary
+func newTestService(t *testing.T, summary string) (*testimony.Service, *testimony.MemoryTestimonyRepository, *repotest.Invalidations, *int) {
+	t.Helper()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Authorization = %q, want the configured key", r.Header.Get("Authorization"))
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": testimony.Message{Role: "assistant", Content: summary}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	repo := testimony.NewMemoryTestimonyRepository()
+	inv := &repotest.Invalidations{}
+	s := testimony.NewService(repo, &config.Config{OpenRouterAPIKey: "test-key"}, inv.Invalidate)
+	testimony.SetSummaryURL(s, server.URL)
+	return s, repo, inv, &calls
+}
+
+func TestCreateTestimonyWaitsForApproval(t *testing.T) {
+	ctx := context.Background()
+	s, repo, inv, _ := newTestService(t, "")
+
+	if err := s.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada Lovelace", Description: "Great", AISummary: "spam"}); err != nil {
+		t.Fatalf("CreateTestimony: %v", err)
+	}
+	got, err := repo.GetTestimonyByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTestimonyByID: %v", err)
+	}
+	if got.AISummary != "" || got.Approved {
+		t.Errorf("testimony = %+v, want no summary and not approved", got)
+	}
+	if got.ProfileUrl != "https://api.dicebear.com/7.x/adventurer/svg?seed=Ada+Lovelace" {
+		t.Errorf("ProfileUrl = %q, want the default avatar", got.ProfileUrl)
+	}
+	if len(*inv) != 0 {
+		t.Errorf("invalidated %v, want nothing for an unapproved testimony", *inv)
+	}
+}
+
+func TestApproveTestimonySummarizesThenApproves(t *testing.T) {
+	ctx := context.Background()
+	s, repo, inv, calls := newTestService(t, `<s> "A reliable developer." </s>`)
+
+	if err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada", Description: "Great to work with"}); err != nil {
+		t.Fatalf("CreateTestimony: %v", err)
+	}
+	if err := s.ApproveTestimony(ctx, &testimony.ApproveTestimonyDto{Approved: true}, 1); err != nil {
+		t.Fatalf("ApproveTestimony: %v", err)
+	}
+
+	got, err := repo.GetTestimonyByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTestimonyByID: %v", err)
+	}
+	if !got.Approved || got.AISummary != "A reliable developer." {
+		t.Errorf("testimony = %+v, want approved with the cleaned summary", got)
+	}
+	if *calls != 1 {
+		t.Errorf("summary requests = %d, want 1", *calls)
+	}
+	if want := (repotest.Invalidations{{cache.TagTestimoniesApproved}}); !slices.EqualFunc(*inv, want, slices.Equal) {
+		t.Errorf("invalidated %v, want %v", *inv, want)
+	}
+
+	// An existing summary is kept when the testimony is approved again
+	if err := s.ApproveTestimony(ctx, &testimony.ApproveTestimonyDto{Approved: true}, 1); err != nil {
+		t.Fatalf("second ApproveTestimony: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("summary requests = %d after approving again, want 1", *calls)
+	}
+}
+
+func TestApproveTestimonyFailsWithoutSummary(t *testing.T) {
+	ctx := context.Background()
+	s, repo, inv, _ := newTestService(t, "")
+	testimony.SetSummaryURL(s, "http://127.0.0.1:0")
+
+	if err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada", Description: "Great"}); err != nil {
+		t.Fatalf("CreateTestimony: %v", err)
+	}
+	if err := s.ApproveTestimony(ctx, &testimony.ApproveTestimonyDto{Approved: true}, 1); err == nil {
+		t.Fatal("ApproveTestimony succeeded without a summary")
+	}
+
+	got, err := repo.GetTestimonyByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTestimonyByID: %v", err)
+	}
+	if got.Approved {
+		t.Error("testimony was approved although its summary failed")
+	}
+	if len(*inv) != 0 {
+		t.Errorf("invalidated %v after a failed approval", *inv)
+	}
+}
+
+func TestUnapproveTestimonySkipsSummary(t *testing.T) {
+	ctx := context.Background()
+	s, repo, inv, calls := newTestService(t, "unused")
+
+	if err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada", Description: "Great", Approved: true}); err != nil {
+		t.Fatalf("CreateTestimony: %v", err)
+	}
+	if err := s.ApproveTestimony(ctx, &testimony.ApproveTestimonyDto{Approved: false}, 1); err != nil {
+		t.Fatalf("ApproveTestimony: %v", err)
+	}
+
+	got, err := repo.GetTestimonyByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTestimonyByID: %v", err)
+	}
+	if got.Approved || *calls != 0 {
+		t.Errorf("testimony = %+v after %d summary requests, want unapproved without any", got, *calls)
+	}
+	if want := (repotest.Invalidations{{cache.TagTestimoniesApproved}}); !slices.EqualFunc(*inv, want, slices.Equal) {
+		t.Errorf("invalidated %v, want %v", *inv, want)
+	}
+}
+
+func TestTestimonyWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	s, repo, inv, _ := newTestService(t, "")
+
+	if err := repo.CreateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada", Description: "Great"}); err != nil {
+		t.Fatalf("CreateTestimony: %v", err)
+	}
+	if err := s.UpdateTestimonyPage(ctx, &testimony.TestimonyPageDto{Title: "Kind words"}); err != nil {
+		t.Fatalf("UpdateTestimonyPage: %v", err)
+	}
+	if err := s.UpdateTestimony(ctx, &testimony.TestimonyItemDto{Name: "Ada", Description: "Even better"}, 1); err != nil {
+		t.Fatalf("UpdateTestimony: %v", err)
+	}
+	if err := s.DeleteTestimony(ctx, 1); err != nil {
+		t.Fatalf("DeleteTestimony: %v", err)
+	}
+
+	want := repotest.Invalidations{{cache.TagTestimonyPage}, {cache.TagTestimoniesApproved}, {cache.TagTestimoniesApproved}}
+	if !slices.EqualFunc(*inv, want, slices.Equal) {
+		t.Errorf("invalidated %v, want %v", *inv, want)
+	}
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// ChallengeTTL is how long a user has to enter their second factor
+const ChallengeTTL = 5 * time.Minute
+
+// maxChallengeAttempts bounds code guesses against a single challenge
+const maxChallengeAttempts = 5
+
+var ErrInvalidChallenge = errors.New("invalid or expired login challenge")
+
+func challengeKey(raw string) string {
+	return "mfa_challenge:" + utils.HashToken(raw)
+}
+
+func (s *RedisStore) IssueChallenge(ctx context.Context, userID string) (string, error) {
+	raw, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	key := challengeKey(raw)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", userID, "attempts", 0)
+	pipe.Expire(ctx, key, ChallengeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// attemptScript counts an attempt against an existing challenge and returns
+// {attempts, user_id}, or nil when the challenge does not exist.
+var attemptScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return nil
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+return {attempts, redis.call("HGET", KEYS[1], "user_id")}
+`)
+
+func (s *RedisStore) ChallengeUser(ctx context.Context, raw string) (string, error) {
+	key := challengeKey(raw)
+
+	res, err := attemptScript.Run(ctx, s.client, []string{key}).Slice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrInvalidChallenge
+		}
+		return "", err
+	}
+
+	attempts, _ := res[0].(int64)
+	userID, _ := res[1].(string)
+	if attempts > maxChallengeAttempts {
+		s.client.Del(ctx, key)
+		return "", ErrInvalidChallenge
+	}
+
+	return userID, nil
+}
+
+func (s *RedisStore) CompleteChallenge(ctx context.Context, raw string) error {
+	return s.client.Del(ctx, challengeKey(raw)).Err()
+}
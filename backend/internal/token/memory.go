@@ -0,0 +1,177 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+// MemoryStore keeps the tokens in the memory of this instance, for
+// deployments without Redis. It only suits a single instance, and a restart
+// signs everyone out and forgets the revoked access tokens that have not
+// expired yet. It is safe for concurrent use.
+type MemoryStore struct {
+	mu              sync.Mutex
+	refreshTTL      time.Duration
+	accessTTL       time.Duration
+	refreshTokens   *utils.ExpiringMap[*memoryRefreshToken] // by token hash
+	revokedFamilies *utils.ExpiringMap[struct{}]
+	revokedTokens   *utils.ExpiringMap[struct{}]         // by jti
+	challenges      *utils.ExpiringMap[*memoryChallenge] // by token hash
+	loginTickets    *utils.ExpiringMap[string]           // user ids, by ticket hash
+}
+
+type memoryRefreshToken struct {
+	RefreshToken
+	used int
+}
+
+type memoryChallenge struct {
+	userID   string
+	attempts int
+}
+
+func NewMemoryStore(accessTTL, refreshTTL time.Duration) *MemoryStore {
+	return &MemoryStore{
+		accessTTL:       accessTTL,
+		refreshTTL:      refreshTTL,
+		refreshTokens:   utils.NewExpiringMap[*memoryRefreshToken](),
+		revokedFamilies: utils.NewExpiringMap[struct{}](),
+		revokedTokens:   utils.NewExpiringMap[struct{}](),
+		challenges:      utils.NewExpiringMap[*memoryChallenge](),
+		loginTickets:    utils.NewExpiringMap[string](),
+	}
+}
+
+func (s *MemoryStore) IssueRefreshToken(ctx context.Context, userID, family string) (string, error) {
+	raw, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshTokens.Set(utils.HashToken(raw), &memoryRefreshToken{
+		RefreshToken: RefreshToken{UserID: userID, Family: family},
+	}, s.refreshTTL)
+	return raw, nil
+}
+
+func (s *MemoryStore) ConsumeRefreshToken(ctx context.Context, raw string) (*RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.refreshTokens.Get(utils.HashToken(raw))
+	if !ok {
+		return nil, ErrInvalidRefreshToken
+	}
+	stored.used++
+	record := stored.RefreshToken
+
+	if stored.used > 1 {
+		s.revokeFamily(record.Family)
+		return &record, ErrRefreshTokenReused
+	}
+	if _, revoked := s.revokedFamilies.Get(record.Family); revoked {
+		return nil, ErrInvalidRefreshToken
+	}
+	return &record, nil
+}
+
+func (s *MemoryStore) RevokeFamily(ctx context.Context, family string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revokeFamily(family)
+	return nil
+}
+
+func (s *MemoryStore) revokeFamily(family string) {
+	s.revokedFamilies.Set(family, struct{}{}, max(s.refreshTTL, s.accessTTL))
+}
+
+func (s *MemoryStore) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revokedTokens.Set(jti, struct{}{}, ttl)
+	return nil
+}
+
+func (s *MemoryStore) IsRevoked(ctx context.Context, jti, family string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.revokedTokens.Get(jti); ok {
+		return true, nil
+	}
+	if family == "" {
+		return false, nil
+	}
+	_, ok := s.revokedFamilies.Get(family)
+	return ok, nil
+}
+
+func (s *MemoryStore) IssueChallenge(ctx context.Context, userID string) (string, error) {
+	raw, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges.Set(utils.HashToken(raw), &memoryChallenge{userID: userID}, ChallengeTTL)
+	return raw, nil
+}
+
+func (s *MemoryStore) ChallengeUser(ctx context.Context, raw string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := utils.HashToken(raw)
+	challenge, ok := s.challenges.Get(key)
+	if !ok {
+		return "", ErrInvalidChallenge
+	}
+	challenge.attempts++
+	if challenge.attempts > maxChallengeAttempts {
+		s.challenges.Delete(key)
+		return "", ErrInvalidChallenge
+	}
+	return challenge.userID, nil
+}
+
+func (s *MemoryStore) CompleteChallenge(ctx context.Context, raw string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges.Delete(utils.HashToken(raw))
+	return nil
+}
+
+func (s *MemoryStore) IssueLoginTicket(ctx context.Context, userID string) (string, error) {
+	raw, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loginTickets.Set(utils.HashToken(raw), userID, LoginTicketTTL)
+	return raw, nil
+}
+
+func (s *MemoryStore) ConsumeLoginTicket(ctx context.Context, raw string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.loginTickets.Delete(utils.HashToken(raw))
+	if !ok {
+		return "", ErrInvalidLoginTicket
+	}
+	return userID, nil
+}
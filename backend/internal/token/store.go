@@ -0,0 +1,181 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+// RefreshToken is the server-side record behind an opaque refresh token
+type RefreshToken struct {
+	UserID string
+	Family string
+}
+
+// Store keeps refresh tokens, the access token denylist, login challenges
+// and login tickets.
+//
+// Every login starts a token family. Each refresh consumes the presented
+// refresh token and issues a new one in the same family; presenting an
+// already consumed token revokes the whole family.
+type Store interface {
+	// IssueRefreshToken creates a new refresh token in the given family
+	IssueRefreshToken(ctx context.Context, userID, family string) (string, error)
+	// ConsumeRefreshToken marks a refresh token as used and returns its
+	// record. A token can only be consumed once; a second attempt revokes
+	// its family and returns the record with ErrRefreshTokenReused, so
+	// callers can clean up the revoked family.
+	ConsumeRefreshToken(ctx context.Context, raw string) (*RefreshToken, error)
+	// RevokeFamily invalidates every refresh and access token of a family
+	RevokeFamily(ctx context.Context, family string) error
+	// RevokeAccessToken denylists a single access token until it expires
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// IsRevoked reports whether an access token or its family has been
+	// revoked
+	IsRevoked(ctx context.Context, jti, family string) (bool, error)
+
+	// IssueChallenge creates a short-lived token proving the password step
+	// succeeded for userID. It must be exchanged together with a second
+	// factor.
+	IssueChallenge(ctx context.Context, userID string) (string, error)
+	// ChallengeUser returns the user a challenge was issued for and counts
+	// the attempt. The challenge is destroyed once it runs out of attempts.
+	ChallengeUser(ctx context.Context, raw string) (string, error)
+	// CompleteChallenge destroys a challenge after a successful second factor
+	CompleteChallenge(ctx context.Context, raw string) error
+
+	// IssueLoginTicket creates a single-use ticket proving userID signed in
+	// with an external identity provider. The ticket travels in a redirect
+	// URL, so it is short-lived and exchanged for tokens in a separate
+	// request.
+	IssueLoginTicket(ctx context.Context, userID string) (string, error)
+	// ConsumeLoginTicket redeems a ticket and returns the user it was issued
+	// for
+	ConsumeLoginTicket(ctx context.Context, raw string) (string, error)
+}
+
+// NewFamily returns a fresh token family identifier
+func NewFamily() (string, error) {
+	return utils.RandomToken(16)
+}
+
+// RedisStore keeps the tokens in Redis, shared by every instance
+type RedisStore struct {
+	client     *redis.Client
+	refreshTTL time.Duration
+	accessTTL  time.Duration
+}
+
+func NewRedisStore(client *redis.Client, accessTTL, refreshTTL time.Duration) *RedisStore {
+	return &RedisStore{
+		client:     client,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+func refreshKey(raw string) string {
+	return "refresh_token:" + utils.HashToken(raw)
+}
+
+func revokedFamilyKey(family string) string {
+	return "revoked_family:" + family
+}
+
+func revokedTokenKey(jti string) string {
+	return "revoked_jti:" + jti
+}
+
+func (s *RedisStore) IssueRefreshToken(ctx context.Context, userID, family string) (string, error) {
+	raw, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	key := refreshKey(raw)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", userID, "family", family, "used", 0)
+	pipe.Expire(ctx, key, s.refreshTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return raw, nil
+}
+
+// consumeScript atomically bumps the use counter of an existing refresh token
+// and returns {used, user_id, family}, or nil when the token does not exist.
+var consumeScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return nil
+end
+local used = redis.call("HINCRBY", KEYS[1], "used", 1)
+local fields = redis.call("HMGET", KEYS[1], "user_id", "family")
+return {used, fields[1], fields[2]}
+`)
+
+func (s *RedisStore) ConsumeRefreshToken(ctx context.Context, raw string) (*RefreshToken, error) {
+	res, err := consumeScript.Run(ctx, s.client, []string{refreshKey(raw)}).Slice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	used, _ := res[0].(int64)
+	userID, _ := res[1].(string)
+	family, _ := res[2].(string)
+	record := &RefreshToken{UserID: userID, Family: family}
+
+	if used > 1 {
+		if err := s.RevokeFamily(ctx, record.Family); err != nil {
+			return nil, err
+		}
+		return record, ErrRefreshTokenReused
+	}
+
+	revoked, err := s.client.Exists(ctx, revokedFamilyKey(record.Family)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if revoked > 0 {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return record, nil
+}
+
+func (s *RedisStore) RevokeFamily(ctx context.Context, family string) error {
+	ttl := max(s.refreshTTL, s.accessTTL)
+	return s.client.Set(ctx, revokedFamilyKey(family), 1, ttl).Err()
+}
+
+func (s *RedisStore) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, revokedTokenKey(jti), 1, ttl).Err()
+}
+
+func (s *RedisStore) IsRevoked(ctx context.Context, jti, family string) (bool, error) {
+	keys := []string{revokedTokenKey(jti)}
+	if family != "" {
+		keys = append(keys, revokedFamilyKey(family))
+	}
+	n, err := s.client.Exists(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
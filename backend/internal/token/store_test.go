@@ -0,0 +1,147 @@
+package token_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/othersidedrl/portfolio/backend/internal/token"
+	"github.com/redis/go-redis/v9"
+)
+
+// forEachStore runs test against a store in Redis, then one in memory
+func forEachStore(t *testing.T, test func(t *testing.T, s token.Store)) {
+	t.Run("redis", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		test(t, token.NewRedisStore(client, time.Minute, time.Hour))
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, token.NewMemoryStore(time.Minute, time.Hour))
+	})
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s token.Store) {
+		ctx := context.Background()
+
+		first, err := s.IssueRefreshToken(ctx, "1", "family")
+		if err != nil {
+			t.Fatalf("IssueRefreshToken: %v", err)
+		}
+		record, err := s.ConsumeRefreshToken(ctx, first)
+		if err != nil {
+			t.Fatalf("ConsumeRefreshToken: %v", err)
+		}
+		if *record != (token.RefreshToken{UserID: "1", Family: "family"}) {
+			t.Errorf("record = %+v, want user 1 in family", *record)
+		}
+		second, err := s.IssueRefreshToken(ctx, "1", "family")
+		if err != nil {
+			t.Fatalf("IssueRefreshToken: %v", err)
+		}
+
+		// Presenting the consumed token again revokes the whole family
+		record, err = s.ConsumeRefreshToken(ctx, first)
+		if !errors.Is(err, token.ErrRefreshTokenReused) || record == nil || record.Family != "family" {
+			t.Fatalf("reusing a token = %+v, %v, want its record with ErrRefreshTokenReused", record, err)
+		}
+		if _, err := s.ConsumeRefreshToken(ctx, second); !errors.Is(err, token.ErrInvalidRefreshToken) {
+			t.Errorf("a token of the revoked family error = %v, want ErrInvalidRefreshToken", err)
+		}
+		if revoked, err := s.IsRevoked(ctx, "jti", "family"); err != nil || !revoked {
+			t.Errorf("IsRevoked for the revoked family = %v, %v, want true", revoked, err)
+		}
+
+		if _, err := s.ConsumeRefreshToken(ctx, "unknown"); !errors.Is(err, token.ErrInvalidRefreshToken) {
+			t.Errorf("an unknown token error = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+}
+
+func TestAccessTokenDenylist(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s token.Store) {
+		ctx := context.Background()
+
+		if err := s.RevokeAccessToken(ctx, "revoked", time.Now().Add(time.Minute)); err != nil {
+			t.Fatalf("RevokeAccessToken: %v", err)
+		}
+		if err := s.RevokeAccessToken(ctx, "expired", time.Now().Add(-time.Minute)); err != nil {
+			t.Fatalf("RevokeAccessToken of an expired token: %v", err)
+		}
+		if err := s.RevokeFamily(ctx, "signed-out"); err != nil {
+			t.Fatalf("RevokeFamily: %v", err)
+		}
+
+		for _, c := range []struct {
+			jti, family string
+			want        bool
+		}{
+			{"revoked", "", true},
+			{"revoked", "other", true},
+			{"valid", "signed-out", true},
+			{"valid", "other", false},
+			{"valid", "", false},
+			{"expired", "", false},
+		} {
+			revoked, err := s.IsRevoked(ctx, c.jti, c.family)
+			if err != nil {
+				t.Fatalf("IsRevoked: %v", err)
+			}
+			if revoked != c.want {
+				t.Errorf("IsRevoked(%q, %q) = %v, want %v", c.jti, c.family, revoked, c.want)
+			}
+		}
+	})
+}
+
+func TestChallengeAttempts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s token.Store) {
+		ctx := context.Background()
+
+		challenge, err := s.IssueChallenge(ctx, "1")
+		if err != nil {
+			t.Fatalf("IssueChallenge: %v", err)
+		}
+		for i := 1; i <= 5; i++ {
+			if userID, err := s.ChallengeUser(ctx, challenge); err != nil || userID != "1" {
+				t.Fatalf("attempt %d = %q, %v, want user 1", i, userID, err)
+			}
+		}
+		for i := 6; i <= 7; i++ {
+			if _, err := s.ChallengeUser(ctx, challenge); !errors.Is(err, token.ErrInvalidChallenge) {
+				t.Errorf("attempt %d error = %v, want ErrInvalidChallenge", i, err)
+			}
+		}
+
+		challenge, err = s.IssueChallenge(ctx, "2")
+		if err != nil {
+			t.Fatalf("IssueChallenge: %v", err)
+		}
+		if err := s.CompleteChallenge(ctx, challenge); err != nil {
+			t.Fatalf("CompleteChallenge: %v", err)
+		}
+		if _, err := s.ChallengeUser(ctx, challenge); !errors.Is(err, token.ErrInvalidChallenge) {
+			t.Errorf("a completed challenge error = %v, want ErrInvalidChallenge", err)
+		}
+	})
+}
+
+func TestLoginTicketIsSingleUse(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s token.Store) {
+		ctx := context.Background()
+
+		ticket, err := s.IssueLoginTicket(ctx, "1")
+		if err != nil {
+			t.Fatalf("IssueLoginTicket: %v", err)
+		}
+		if userID, err := s.ConsumeLoginTicket(ctx, ticket); err != nil || userID != "1" {
+			t.Fatalf("ConsumeLoginTicket = %q, %v, want user 1", userID, err)
+		}
+		if _, err := s.ConsumeLoginTicket(ctx, ticket); !errors.Is(err, token.ErrInvalidLoginTicket) {
+			t.Errorf("a redeemed ticket error = %v, want ErrInvalidLoginTicket", err)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginTicketTTL is how long the CMS has to redeem a ticket after an
+// external login redirected back to it
+const LoginTicketTTL = time.Minute
+
+var ErrInvalidLoginTicket = errors.New("invalid or expired login ticket")
+
+func loginTicketKey(raw string) string {
+	return "login_ticket:" + utils.HashToken(raw)
+}
+
+func (s *RedisStore) IssueLoginTicket(ctx context.Context, userID string) (string, error) {
+	raw, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	if err := s.client.Set(ctx, loginTicketKey(raw), userID, LoginTicketTTL).Err(); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (s *RedisStore) ConsumeLoginTicket(ctx context.Context, raw string) (string, error) {
+	userID, err := s.client.GetDel(ctx, loginTicketKey(raw)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrInvalidLoginTicket
+		}
+		return "", err
+	}
+	return userID, nil
+}
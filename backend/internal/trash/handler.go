@@ -0,0 +1,93 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+// Handler serves the trash of each kind on its own route, so that routes can
+// require the scope that deletes that kind
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetTrash handles GET /admin/trash/{kind}
+func (h *Handler) GetTrash(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trash, err := h.service.GetTrash(r.Context(), kind)
+		if err != nil {
+			utils.WriteServerError(w, err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"length": len(trash.Items),
+			"data":   trash.Items,
+		})
+	}
+}
+
+// Restore handles POST /admin/trash/{kind}/{id}/restore
+func (h *Handler) Restore(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		if err := h.service.Restore(r.Context(), kind, id); err != nil {
+			writeTrashError(w, err)
+			return
+		}
+		logger.Info("Restored from trash", "kind", kind, "id", id, "by", actor(r))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Purge handles DELETE /admin/trash/{kind}/{id}
+func (h *Handler) Purge(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := idParam(w, r)
+		if !ok {
+			return
+		}
+		if err := h.service.Purge(r.Context(), kind, id); err != nil {
+			writeTrashError(w, err)
+			return
+		}
+		logger.Info("Purged from trash", "kind", kind, "id", id, "by", actor(r))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func writeTrashError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotInTrash) {
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.WriteServerError(w, err)
+}
+
+func idParam(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid item ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func actor(r *http.Request) string {
+	if claims := middleware.GetUserFromContext(r.Context()); claims != nil {
+		return claims.Sub
+	}
+	return ""
+}
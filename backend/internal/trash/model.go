@@ -0,0 +1,43 @@
+package trash
+
+import (
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+)
+
+// Kind names a type of content that goes to the trash when deleted
+type Kind string
+
+const (
+	KindProjects    Kind = "projects"
+	KindTestimonies Kind = "testimonies"
+	KindSkills      Kind = "skills"
+	KindCareers     Kind = "careers"
+)
+
+// Kinds lists every kind of content that can be trashed
+var Kinds = []Kind{KindProjects, KindTestimonies, KindSkills, KindCareers}
+
+// cacheTags are the cached public responses a restored item of each kind
+// shows up in. A trashed testimony is hidden whether or not it was approved,
+// so restoring one may bring it back to the approved list.
+var cacheTags = map[Kind]cache.Tag{
+	KindProjects:    cache.TagProjects,
+	KindTestimonies: cache.TagTestimoniesApproved,
+	KindSkills:      cache.TagSkills,
+	KindCareers:     cache.TagCareers,
+}
+
+type TrashItemDto struct {
+	ID        uint      `json:"id"`
+	Kind      Kind      `json:"kind"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// PurgeAt is when retention deletes the item for good
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+type TrashDto struct {
+	Items []TrashItemDto `json:"items"`
+}
@@ -0,0 +1,123 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/revision"
+	"gorm.io/gorm"
+)
+
+// TrashedRecord is a soft-deleted row of any kind
+type TrashedRecord struct {
+	ID        uint
+	Title     string
+	DeletedAt time.Time
+}
+
+// TrashRepository reads and manages soft-deleted content
+type TrashRepository interface {
+	GetTrashed(ctx context.Context, kind Kind) ([]TrashedRecord, error)
+	RestoreTrashed(ctx context.Context, kind Kind, id uint) (bool, error)
+	PurgeTrashed(ctx context.Context, kind Kind, id uint) (bool, error)
+	PurgeTrashedBefore(ctx context.Context, kind Kind, cutoff time.Time) (int64, error)
+}
+
+// trashTable maps a kind to its model, the column shown as its title and the
+// kind its revisions are recorded under, if any
+type trashTable struct {
+	model        interface{}
+	titleColumn  string
+	revisionKind revision.Kind
+}
+
+var trashTables = map[Kind]trashTable{
+	KindProjects:    {model: &models.Project{}, titleColumn: "name", revisionKind: revision.KindProjects},
+	KindTestimonies: {model: &models.Testimony{}, titleColumn: "name"},
+	KindSkills:      {model: &models.TechnicalSkills{}, titleColumn: "name", revisionKind: revision.KindSkills},
+	KindCareers:     {model: &models.CareerJourney{}, titleColumn: "title", revisionKind: revision.KindCareers},
+}
+
+type GormTrashRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTrashRepository(db *gorm.DB) *GormTrashRepository {
+	return &GormTrashRepository{db: db}
+}
+
+// GetTrashed lists soft-deleted rows, most recently deleted first
+func (r *GormTrashRepository) GetTrashed(ctx context.Context, kind Kind) ([]TrashedRecord, error) {
+	table, err := tableFor(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []TrashedRecord
+	err = r.db.WithContext(ctx).Unscoped().Model(table.model).
+		Select("id", table.titleColumn+" AS title", "deleted_at").
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Scan(&records).Error
+	return records, err
+}
+
+// RestoreTrashed clears deleted_at; it reports false if the row is not in the trash
+func (r *GormTrashRepository) RestoreTrashed(ctx context.Context, kind Kind, id uint) (bool, error) {
+	table, err := tableFor(kind)
+	if err != nil {
+		return false, err
+	}
+
+	var restored bool
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(table.model).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		restored = result.RowsAffected > 0
+		if !restored || table.revisionKind == "" {
+			return nil
+		}
+		return revision.RecordID(tx, table.revisionKind, models.RevisionRestore, id)
+	})
+	return restored, err
+}
+
+// PurgeTrashed permanently deletes a trashed row; live rows are never touched
+func (r *GormTrashRepository) PurgeTrashed(ctx context.Context, kind Kind, id uint) (bool, error) {
+	table, err := tableFor(kind)
+	if err != nil {
+		return false, err
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Delete(table.model)
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeTrashedBefore permanently deletes rows trashed before cutoff
+func (r *GormTrashRepository) PurgeTrashedBefore(ctx context.Context, kind Kind, cutoff time.Time) (int64, error) {
+	table, err := tableFor(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Delete(table.model)
+	return result.RowsAffected, result.Error
+}
+
+func tableFor(kind Kind) (trashTable, error) {
+	table, ok := trashTables[kind]
+	if !ok {
+		return trashTable{}, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	return table, nil
+}
@@ -0,0 +1,109 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/cache"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+)
+
+var (
+	ErrUnknownKind = errors.New("unknown content kind")
+	ErrNotInTrash  = errors.New("item not found in trash")
+)
+
+type Service struct {
+	repo       TrashRepository
+	retention  time.Duration
+	invalidate cache.Invalidator
+}
+
+// NewService keeps trashed items for retention before purging them and calls
+// invalidate after an item was restored. Purging changes no public content.
+func NewService(repo TrashRepository, retention time.Duration, invalidate cache.Invalidator) *Service {
+	return &Service{repo: repo, retention: retention, invalidate: invalidate}
+}
+
+// GetTrash lists the trashed items of one kind
+func (s *Service) GetTrash(ctx context.Context, kind Kind) (*TrashDto, error) {
+	records, err := s.repo.GetTrashed(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]TrashItemDto, 0, len(records))
+	for _, rec := range records {
+		items = append(items, TrashItemDto{
+			ID:        rec.ID,
+			Kind:      kind,
+			Title:     rec.Title,
+			DeletedAt: rec.DeletedAt,
+			PurgeAt:   rec.DeletedAt.Add(s.retention),
+		})
+	}
+	return &TrashDto{Items: items}, nil
+}
+
+// Restore moves an item out of the trash
+func (s *Service) Restore(ctx context.Context, kind Kind, id uint) error {
+	restored, err := s.repo.RestoreTrashed(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return ErrNotInTrash
+	}
+	s.invalidate(ctx, cacheTags[kind])
+	return nil
+}
+
+// Purge permanently deletes an item that is in the trash
+func (s *Service) Purge(ctx context.Context, kind Kind, id uint) error {
+	purged, err := s.repo.PurgeTrashed(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if !purged {
+		return ErrNotInTrash
+	}
+	return nil
+}
+
+// PurgeExpired permanently deletes every item trashed longer than the
+// retention period and returns how many were removed
+func (s *Service) PurgeExpired(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-s.retention)
+
+	var total int64
+	for _, kind := range Kinds {
+		n, err := s.repo.PurgeTrashedBefore(ctx, kind, cutoff)
+		if err != nil {
+			return total, err
+		}
+		if n > 0 {
+			logger.Info("Purged expired trash", "kind", kind, "count", n)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// RunRetention purges expired items now and then every interval until ctx
+// is cancelled
+func (s *Service) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PurgeExpired(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to purge expired trash", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.GetUsers(r.Context())
+	if err != nil {
+		utils.WriteServerError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"length": len(users.Users),
+		"data":   users.Users,
+	})
+}
+
+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	user, err := h.service.GetUser(r.Context(), uint(id))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, user)
+}
+
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var body CreateUserDto
+	if err := utils.DecodeBody(r, &body); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user, err := h.service.CreateUser(r.Context(), &body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, user)
+}
+
+func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	var body UpdateUserDto
+	if err := utils.DecodeBody(r, &body); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user, err := h.service.UpdateUser(r.Context(), &body, uint(id))
+	if err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
+		writeServiceError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, user)
+}
+
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if err := h.service.DeleteUser(r.Context(), uint(id)); err != nil {
+		if middleware.WritePreconditionFailed(w, r, err) {
+			return
+		}
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetupTOTP handles POST /auth/totp/setup for the signed-in user
+func (h *Handler) SetupTOTP(w http.ResponseWriter, r *http.Request) {
+	id, ok := currentUserID(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	setup, err := h.service.BeginTOTPEnrollment(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, setup)
+}
+
+// EnableTOTP handles POST /auth/totp/enable and returns the recovery codes once
+func (h *Handler) EnableTOTP(w http.ResponseWriter, r *http.Request) {
+	id, ok := currentUserID(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	var body TOTPCodeDto
+	if err := utils.DecodeBody(r, &body); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	codes, err := h.service.ConfirmTOTPEnrollment(r.Context(), id, body.Code)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, codes)
+}
+
+// DisableTOTP handles POST /auth/totp/disable
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	id, ok := currentUserID(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	var body TOTPCodeDto
+	if err := utils.DecodeBody(r, &body); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.service.DisableTOTP(r.Context(), id, body.Code); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes handles POST /auth/totp/recovery-codes
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	id, ok := currentUserID(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	var body TOTPCodeDto
+	if err := utils.DecodeBody(r, &body); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	codes, err := h.service.RegenerateRecoveryCodes(r.Context(), id, body.Code)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, codes)
+}
+
+// currentUserID reads the signed-in user's ID from the JWT claims
+func currentUserID(r *http.Request) (uint, bool) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(claims.Sub, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrEmailTaken), errors.Is(err, ErrLastOwner),
+		errors.Is(err, ErrTOTPAlreadyEnabled), errors.Is(err, ErrTOTPNotEnabled), errors.Is(err, ErrTOTPNotEnrolling):
+		utils.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidRole), errors.Is(err, ErrInvalidEmail), errors.Is(err, ErrWeakPassword):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrInvalidTOTPCode):
+		utils.WriteError(w, http.StatusUnauthorized, err.Error())
+	default:
+		utils.WriteServerError(w, err)
+	}
+}
@@ -0,0 +1,45 @@
+package user
+
+import "github.com/othersidedrl/portfolio/backend/internal/models"
+
+type UserItemDto struct {
+	ID          uint            `json:"id"`
+	Email       string          `json:"email"`
+	Name        string          `json:"name"`
+	Role        models.UserRole `json:"role"`
+	TOTPEnabled bool            `json:"totp_enabled"`
+	CreatedAt   string          `json:"created_at"`
+}
+
+type UserDto struct {
+	Users []UserItemDto `json:"users"`
+}
+
+type CreateUserDto struct {
+	Email    string          `json:"email"`
+	Name     string          `json:"name"`
+	Password string          `json:"password"`
+	Role     models.UserRole `json:"role"`
+}
+
+// UpdateUserDto is a partial update: nil fields were left out of the request
+// and are kept, set fields are written even when empty
+type UpdateUserDto struct {
+	Email    *string          `json:"email"`
+	Name     *string          `json:"name"`
+	Password *string          `json:"password"`
+	Role     *models.UserRole `json:"role"`
+}
+
+type TOTPSetupDto struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TOTPCodeDto struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesDto struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
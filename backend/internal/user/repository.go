@@ -0,0 +1,136 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"github.com/othersidedrl/portfolio/backend/internal/version"
+	"gorm.io/gorm"
+)
+
+type UserRepository interface {
+	GetUsers(ctx context.Context) ([]models.User, error)
+	GetUserByID(ctx context.Context, id uint) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	UpdateUser(ctx context.Context, user *models.User) error
+	DeleteUser(ctx context.Context, id uint) error
+	CountUsersByRole(ctx context.Context, role models.UserRole) (int64, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error)
+	AdvanceTOTPCounter(ctx context.Context, userID uint, counter int64) (bool, error)
+}
+
+type GormUserRepository struct {
+	db *gorm.DB
+}
+
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
+}
+
+func (r *GormUserRepository) GetUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	if err := r.db.WithContext(ctx).Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *GormUserRepository) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *GormUserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *GormUserRepository) CreateUser(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+func (r *GormUserRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.User{}, user.ID); err != nil {
+			return err
+		}
+		return tx.Save(user).Error
+	})
+}
+
+func (r *GormUserRepository) DeleteUser(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := version.LockItem(ctx, tx, &models.User{}, id); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.APIKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.Session{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Unscoped().Delete(&models.User{}).Error
+	})
+}
+
+func (r *GormUserRepository) CountUsersByRole(ctx context.Context, role models.UserRole) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// ReplaceRecoveryCodes discards a user's recovery codes and stores the new hashes
+func (r *GormUserRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(hashes) == 0 {
+			return nil
+		}
+		codes := make([]models.RecoveryCode, 0, len(hashes))
+		for _, hash := range hashes {
+			codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// AdvanceTOTPCounter records counter as the last TOTP time step used, in one
+// conditional update so concurrent logins cannot both use a code. It reports
+// false when that step or a later one was already used.
+func (r *GormUserRepository) AdvanceTOTPCounter(ctx context.Context, userID uint, counter int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND (totp_last_counter IS NULL OR totp_last_counter < ?)", userID, counter).
+		UpdateColumn("totp_last_counter", counter)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used.
+// It reports false when the code does not exist or was already used.
+func (r *GormUserRepository) UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
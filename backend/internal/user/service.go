@@ -0,0 +1,246 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/argon2id"
+	"github.com/othersidedrl/portfolio/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+const minPasswordLength = 8
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidRole        = errors.New("invalid role: must be owner, editor or moderator")
+	ErrInvalidEmail       = errors.New("invalid email address")
+	ErrWeakPassword       = fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	ErrEmailTaken         = errors.New("email is already in use")
+	ErrLastOwner          = errors.New("cannot remove or demote the last owner")
+)
+
+// dummyHash is compared against when an email is unknown so that a failed
+// lookup costs the same as a failed password check.
+var dummyHash, _ = argon2id.CreateHash("portfolio-dummy-password", argon2id.DefaultParams)
+
+type Service struct {
+	repo       UserRepository
+	totpIssuer string
+}
+
+func NewService(repo UserRepository, totpIssuer string) *Service {
+	return &Service{repo, totpIssuer}
+}
+
+// Authenticate verifies an email/password pair and returns the matching user
+func (s *Service) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := s.repo.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			argon2id.ComparePasswordAndHash(password, dummyHash)
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	match, err := argon2id.ComparePasswordAndHash(password, user.PasswordHash)
+	if err != nil || !match {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+func (s *Service) GetUsers(ctx context.Context) (*UserDto, error) {
+	users, err := s.repo.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtoUsers := []UserItemDto{}
+	for _, u := range users {
+		dtoUsers = append(dtoUsers, toDto(&u))
+	}
+	return &UserDto{Users: dtoUsers}, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, id uint) (*UserItemDto, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dto := toDto(user)
+	return &dto, nil
+}
+
+func (s *Service) CreateUser(ctx context.Context, data *CreateUserDto) (*UserItemDto, error) {
+	email := normalizeEmail(data.Email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, ErrInvalidEmail
+	}
+	if !data.Role.Valid() {
+		return nil, ErrInvalidRole
+	}
+	if len(data.Password) < minPasswordLength {
+		return nil, ErrWeakPassword
+	}
+	if err := s.ensureEmailAvailable(ctx, email, 0); err != nil {
+		return nil, err
+	}
+
+	hash, err := argon2id.CreateHash(data.Password, argon2id.DefaultParams)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:        email,
+		Name:         strings.TrimSpace(data.Name),
+		PasswordHash: hash,
+		Role:         data.Role,
+	}
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	dto := toDto(user)
+	return &dto, nil
+}
+
+// UpdateUser applies the fields set in data to the user. A name can be
+// cleared; an empty email, role or password is rejected like on create.
+func (s *Service) UpdateUser(ctx context.Context, data *UpdateUserDto, id uint) (*UserItemDto, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Email != nil {
+		email := normalizeEmail(*data.Email)
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, ErrInvalidEmail
+		}
+		if err := s.ensureEmailAvailable(ctx, email, user.ID); err != nil {
+			return nil, err
+		}
+		user.Email = email
+	}
+
+	if data.Name != nil {
+		user.Name = strings.TrimSpace(*data.Name)
+	}
+
+	if data.Role != nil && *data.Role != user.Role {
+		if !data.Role.Valid() {
+			return nil, ErrInvalidRole
+		}
+		if user.Role == models.RoleOwner {
+			if err := s.ensureAnotherOwner(ctx); err != nil {
+				return nil, err
+			}
+		}
+		user.Role = *data.Role
+	}
+
+	if data.Password != nil {
+		if len(*data.Password) < minPasswordLength {
+			return nil, ErrWeakPassword
+		}
+		hash, err := argon2id.CreateHash(*data.Password, argon2id.DefaultParams)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = hash
+	}
+
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	dto := toDto(user)
+	return &dto, nil
+}
+
+func (s *Service) DeleteUser(ctx context.Context, id uint) error {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Role == models.RoleOwner {
+		if err := s.ensureAnotherOwner(ctx); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteUser(ctx, id)
+}
+
+// GetUserByEmail looks up a user by email, e.g. one verified by an external
+// identity provider
+func (s *Service) GetUserByEmail(ctx context.Context, email string) (*UserItemDto, error) {
+	user, err := s.repo.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	dto := toDto(user)
+	return &dto, nil
+}
+
+func (s *Service) findUser(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *Service) ensureEmailAvailable(ctx context.Context, email string, selfID uint) error {
+	existing, err := s.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != selfID {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// ensureAnotherOwner guards against locking everyone out of user management
+func (s *Service) ensureAnotherOwner(ctx context.Context) error {
+	owners, err := s.repo.CountUsersByRole(ctx, models.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func toDto(u *models.User) UserItemDto {
+	return UserItemDto{
+		ID:          u.ID,
+		Email:       u.Email,
+		Name:        u.Name,
+		Role:        u.Role,
+		TOTPEnabled: u.TOTPEnabled,
+		CreatedAt:   u.CreatedAt.Format(time.RFC3339),
+	}
+}
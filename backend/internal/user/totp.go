@@ -0,0 +1,188 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+const recoveryCodeCount = 10
+
+// recoveryAlphabet avoids characters that are easily confused (0/o, 1/l/i)
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+var (
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTOTPNotEnrolling   = errors.New("start two-factor setup before enabling it")
+	ErrInvalidTOTPCode    = errors.New("invalid two-factor code")
+)
+
+// BeginTOTPEnrollment generates a new pending secret for the user.
+// The secret is not enforced until ConfirmTOTPEnrollment succeeds.
+func (s *Service) BeginTOTPEnrollment(ctx context.Context, id uint) (*TOTPSetupDto, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	user.TOTPLastCounter = 0
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &TOTPSetupDto{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment enables two-factor authentication once the user proves
+// their authenticator produces valid codes, and returns fresh recovery codes.
+func (s *Service) ConfirmTOTPEnrollment(ctx context.Context, id uint, code string) (*RecoveryCodesDto, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPNotEnrolling
+	}
+
+	counter, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	codes, err := s.regenerateRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastCounter = counter
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off after checking a current code
+func (s *Service) DisableTOTP(ctx context.Context, id uint, code string) error {
+	if err := s.VerifySecondFactor(ctx, id, code, ""); err != nil {
+		return err
+	}
+
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastCounter = 0
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	return s.repo.ReplaceRecoveryCodes(ctx, id, nil)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a current code
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, id uint, code string) (*RecoveryCodesDto, error) {
+	if err := s.VerifySecondFactor(ctx, id, code, ""); err != nil {
+		return nil, err
+	}
+	return s.regenerateRecoveryCodes(ctx, id)
+}
+
+// VerifySecondFactor accepts either a TOTP code or an unused recovery code.
+// Each TOTP code is accepted at most once.
+func (s *Service) VerifySecondFactor(ctx context.Context, id uint, code, recoveryCode string) error {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+
+	if recoveryCode != "" {
+		used, err := s.repo.UseRecoveryCode(ctx, user.ID, utils.HashToken(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTOTPCode
+		}
+		return nil
+	}
+
+	counter, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return ErrInvalidTOTPCode
+	}
+	advanced, err := s.repo.AdvanceTOTPCounter(ctx, user.ID, counter)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return ErrInvalidTOTPCode
+	}
+	return nil
+}
+
+func (s *Service) regenerateRecoveryCodes(ctx context.Context, userID uint) (*RecoveryCodesDto, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &RecoveryCodesDto{RecoveryCodes: codes}, nil
+}
+
+// generateRecoveryCode returns a code formatted as "xxxxx-xxxxx"
+func generateRecoveryCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(recoveryAlphabet)))
+	for i := range 10 {
+		if i == 5 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(recoveryAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,74 @@
+package utils
+
+import "time"
+
+// expiringSweepInterval is how often an ExpiringMap drops the entries that
+// expired without being read again
+const expiringSweepInterval = time.Minute
+
+// ExpiringMap holds values that expire after a TTL, for state an instance
+// keeps in its own memory rather than in Redis. It is not safe for
+// concurrent use: its owner serializes access, which also makes a read and
+// the write depending on it atomic, like a Redis script.
+type ExpiringMap[V any] struct {
+	entries map[string]expiringEntry[V]
+	swept   time.Time
+}
+
+type expiringEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+func NewExpiringMap[V any]() *ExpiringMap[V] {
+	return &ExpiringMap[V]{entries: map[string]expiringEntry[V]{}}
+}
+
+// Get returns the value of key, unless it is missing or expired
+func (m *ExpiringMap[V]) Get(key string) (V, bool) {
+	entry, ok := m.entries[key]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set stores value under key for ttl, replacing its value and TTL. At most
+// once per expiringSweepInterval it also drops the expired entries, so keys
+// that are never read again do not pile up.
+func (m *ExpiringMap[V]) Set(key string, value V, ttl time.Duration) {
+	now := time.Now()
+	m.sweep(now)
+	m.entries[key] = expiringEntry[V]{value: value, expiresAt: now.Add(ttl)}
+}
+
+// Delete drops key and returns the value it held, unless it had expired
+func (m *ExpiringMap[V]) Delete(key string) (V, bool) {
+	value, ok := m.Get(key)
+	delete(m.entries, key)
+	return value, ok
+}
+
+// Range calls fn with every entry that has not expired, in no particular
+// order
+func (m *ExpiringMap[V]) Range(fn func(key string, value V)) {
+	now := time.Now()
+	for key, entry := range m.entries {
+		if now.Before(entry.expiresAt) {
+			fn(key, entry.value)
+		}
+	}
+}
+
+func (m *ExpiringMap[V]) sweep(now time.Time) {
+	if now.Sub(m.swept) < expiringSweepInterval {
+		return
+	}
+	m.swept = now
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
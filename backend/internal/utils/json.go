@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 )
 
@@ -17,3 +19,80 @@ func DecodeBody[T any](r *http.Request, dst *T) error {
 	}
 	return nil
 }
+
+// PatchBody applies the JSON merge patch (RFC 7396) in the body of r to dst,
+// which holds the current state of the resource. Fields the patch leaves out
+// keep their value, fields it sets are written even when empty, and fields it
+// sets to null are cleared. Arrays are replaced as a whole.
+func PatchBody[T any](r *http.Request, dst *T) error {
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		return ErrInvalidJSON
+	}
+	current, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+	merged, err := MergePatch(current, patch)
+	if err != nil {
+		return ErrInvalidJSON
+	}
+
+	var patched T
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patched); err != nil {
+		return ErrInvalidJSON
+	}
+	*dst = patched
+	return nil
+}
+
+// MergePatch applies the JSON merge patch patch, which must be an object, to
+// the JSON document doc
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var p interface{}
+	if err := unmarshalNumbers(patch, &p); err != nil {
+		return nil, err
+	}
+	if _, ok := p.(map[string]interface{}); !ok {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+	var d interface{}
+	if err := unmarshalNumbers(doc, &d); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeValue(d, p))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergeValue(targetObj[key], value)
+	}
+	return targetObj
+}
+
+// unmarshalNumbers decodes one JSON value, keeping numbers exact
+func unmarshalNumbers(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
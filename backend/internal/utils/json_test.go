@@ -0,0 +1,130 @@
+package utils_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/othersidedrl/portfolio/backend/internal/utils"
+)
+
+type link struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+type profile struct {
+	Name    string   `json:"name"`
+	Bio     string   `json:"bio"`
+	Skills  []string `json:"skills"`
+	Link    *link    `json:"link"`
+	Visible bool     `json:"visible"`
+}
+
+func current() profile {
+	return profile{
+		Name:    "Ada",
+		Bio:     "Engineer",
+		Skills:  []string{"Go", "SQL"},
+		Link:    &link{Label: "Site", URL: "https://example.com"},
+		Visible: true,
+	}
+}
+
+func patch(t *testing.T, body string) (profile, error) {
+	t.Helper()
+	dst := current()
+	r := httptest.NewRequest("PATCH", "/", strings.NewReader(body))
+	err := utils.PatchBody(r, &dst)
+	return dst, err
+}
+
+func TestPatchBodyKeepsAbsentFields(t *testing.T) {
+	got, err := patch(t, `{"bio":"Writer"}`)
+	if err != nil {
+		t.Fatalf("PatchBody: %v", err)
+	}
+	want := current()
+	want.Bio = "Writer"
+	if got.Name != want.Name || got.Bio != want.Bio || !slices.Equal(got.Skills, want.Skills) ||
+		*got.Link != *want.Link || got.Visible != want.Visible {
+		t.Errorf("patched = %+v, want %+v", got, want)
+	}
+}
+
+func TestPatchBodyWritesEmptyValues(t *testing.T) {
+	got, err := patch(t, `{"bio":"","visible":false}`)
+	if err != nil {
+		t.Fatalf("PatchBody: %v", err)
+	}
+	if got.Bio != "" || got.Visible {
+		t.Errorf("bio = %q, visible = %v, want both cleared", got.Bio, got.Visible)
+	}
+}
+
+func TestPatchBodyNullClears(t *testing.T) {
+	got, err := patch(t, `{"bio":null,"skills":null,"link":null}`)
+	if err != nil {
+		t.Fatalf("PatchBody: %v", err)
+	}
+	if got.Bio != "" || got.Skills != nil || got.Link != nil {
+		t.Errorf("patched = %+v, want bio, skills and link cleared", got)
+	}
+	if got.Name != "Ada" {
+		t.Errorf("Name = %q, want the untouched Ada", got.Name)
+	}
+}
+
+func TestPatchBodyReplacesArrays(t *testing.T) {
+	got, err := patch(t, `{"skills":["Rust"]}`)
+	if err != nil {
+		t.Fatalf("PatchBody: %v", err)
+	}
+	if !slices.Equal(got.Skills, []string{"Rust"}) {
+		t.Errorf("Skills = %q, want [Rust]", got.Skills)
+	}
+}
+
+func TestPatchBodyMergesNestedObjects(t *testing.T) {
+	got, err := patch(t, `{"link":{"url":"https://example.org"}}`)
+	if err != nil {
+		t.Fatalf("PatchBody: %v", err)
+	}
+	if want := (link{Label: "Site", URL: "https://example.org"}); got.Link == nil || *got.Link != want {
+		t.Errorf("Link = %+v, want %+v", got.Link, want)
+	}
+}
+
+func TestPatchBodyRejectsNonObjects(t *testing.T) {
+	for _, body := range []string{`["bio"]`, `"Ada"`, `null`, `{"bio":"Writer"} {}`, `{`} {
+		got, err := patch(t, body)
+		if !errors.Is(err, utils.ErrInvalidJSON) {
+			t.Errorf("PatchBody(%s) error = %v, want ErrInvalidJSON", body, err)
+		}
+		if got.Bio != "Engineer" {
+			t.Errorf("PatchBody(%s) changed the destination to %+v", body, got)
+		}
+	}
+}
+
+func TestPatchBodyRejectsUnknownFields(t *testing.T) {
+	got, err := patch(t, `{"bio":"Writer","role":"admin"}`)
+	if !errors.Is(err, utils.ErrInvalidJSON) {
+		t.Errorf("error = %v, want ErrInvalidJSON", err)
+	}
+	if got.Bio != "Engineer" {
+		t.Errorf("a rejected patch changed the destination to %+v", got)
+	}
+}
+
+func TestMergePatchKeepsNumbersExact(t *testing.T) {
+	got, err := utils.MergePatch([]byte(`{"id":9007199254740993,"n":1}`), []byte(`{"n":2}`))
+	if err != nil {
+		t.Fatalf("MergePatch: %v", err)
+	}
+	if want := `{"id":9007199254740993,"n":2}`; string(got) != want {
+		t.Errorf("MergePatch = %s, want %s", got, want)
+	}
+}
@@ -2,52 +2,124 @@ package utils
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// JWTClaims are the claims of an access token. RegisteredClaims.ID carries
+// the jti used for revocation, Family the refresh token family it belongs to.
 type JWTClaims struct {
-	Sub string `json:"sub"`
+	Sub    string `json:"sub"`
+	Role   string `json:"role"`
+	Family string `json:"fam,omitempty"`
+	jwt.RegisteredClaims
+
+	// APIKeyID and Scopes are only set when the request authenticated with an
+	// API key instead of a JWT; they are never serialized into tokens.
+	APIKeyID uint     `json:"-"`
+	Scopes   []string `json:"-"`
+}
+
+// PreviewAudience marks tokens that only grant read access to one content
+// draft; they are never accepted as access tokens
+const PreviewAudience = "preview"
+
+// PreviewClaims are the claims of a draft preview token
+type PreviewClaims struct {
+	Kind    string `json:"kind"`
+	DraftID uint   `json:"draft"`
 	jwt.RegisteredClaims
 }
 
 type JWTService struct {
-	secret []byte
+	keys *KeyStore
+	ttl  time.Duration
 }
 
-func NewJWTService(secret string) *JWTService {
+func NewJWTService(keys *KeyStore, ttl time.Duration) *JWTService {
 	return &JWTService{
-		secret: []byte(secret),
+		keys: keys,
+		ttl:  ttl,
 	}
 }
 
-func (j *JWTService) GenerateToken(userID string) (string, error) {
+// Keys returns the key store used to sign and verify tokens
+func (j *JWTService) Keys() *KeyStore {
+	return j.keys
+}
+
+// TTL returns the lifetime of generated access tokens
+func (j *JWTService) TTL() time.Duration {
+	return j.ttl
+}
+
+func (j *JWTService) GenerateToken(userID, role, family string) (string, error) {
+	jti, err := RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	claims := JWTClaims{
-		Sub: userID,
+		Sub:    userID,
+		Role:   role,
+		Family: family,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(72 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+	return j.keys.sign(claims)
 }
 
 func (j *JWTService) VerifyToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return j.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, j.keys.keyFunc,
+		jwt.WithValidMethods(j.keys.validMethods()))
 
 	if err != nil {
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid || slices.Contains(claims.Audience, PreviewAudience) {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// GeneratePreviewToken signs a token that lets its holder read one draft
+// until it expires after ttl
+func (j *JWTService) GeneratePreviewToken(kind string, draftID uint, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := PreviewClaims{
+		Kind:    kind,
+		DraftID: draftID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{PreviewAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := j.keys.sign(claims)
+	return token, expiresAt, err
+}
+
+func (j *JWTService) VerifyPreviewToken(tokenString string) (*PreviewClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &PreviewClaims{}, j.keys.keyFunc,
+		jwt.WithValidMethods(j.keys.validMethods()), jwt.WithAudience(PreviewAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*PreviewClaims)
 	if !ok || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
@@ -0,0 +1,383 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/logger"
+)
+
+// Supported asymmetric signing algorithms
+const (
+	AlgEdDSA = "EdDSA"
+	AlgRS256 = "RS256"
+)
+
+// minRSABits rejects RSA keys too short to be safe
+const minRSABits = 2048
+
+// hmacWarnInterval spaces the warnings about accepted HS256 tokens while
+// migrating away from the shared secret
+const hmacWarnInterval = time.Minute
+
+// SigningKey is an asymmetric key identified by its RFC 7638 thumbprint
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer // nil for verification-only keys
+	Public    crypto.PublicKey
+}
+
+// JWK is the public half of a key as published in a JWKS document
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeyStore holds the key used to sign new tokens and every key still
+// accepted for verification. Rotating keys means adding the new key as the
+// signing key and keeping the old one as a verification key until all tokens
+// it signed have expired.
+//
+// A store may also hold a shared HMAC secret for HS256 tokens. HS256 is used
+// for signing only when no asymmetric signing key is configured, and is never
+// published in the JWKS. Next to a signing key, HS256 tokens are accepted
+// only until hmacUntil, while migrating to the key.
+type KeyStore struct {
+	signing      *SigningKey
+	verification map[string]*SigningKey
+	hmacSecret   []byte
+	hmacUntil    time.Time
+	hmacWarnedAt atomic.Int64
+}
+
+// NewKeyStore builds a key store. signing may be nil when hmacSecret is set;
+// otherwise hmacSecret is only accepted for verification until hmacUntil.
+func NewKeyStore(signing *SigningKey, verification []*SigningKey, hmacSecret string, hmacUntil time.Time) (*KeyStore, error) {
+	if signing == nil && hmacSecret == "" {
+		return nil, errors.New("either a signing key or an HMAC secret is required")
+	}
+	if signing != nil && signing.Private == nil {
+		return nil, fmt.Errorf("signing key %s has no private key", signing.ID)
+	}
+
+	if signing != nil && hmacSecret != "" {
+		if hmacUntil.IsZero() {
+			logger.Warn("JWT_SECRET is ignored since a signing key is set; set JWT_SECRET_ACCEPTED_UNTIL to accept HS256 tokens while migrating")
+			hmacSecret = ""
+		} else if time.Now().Before(hmacUntil) {
+			logger.Warn("Accepting HS256 tokens signed with JWT_SECRET during the migration to the signing key", "until", hmacUntil)
+		}
+	}
+
+	ks := &KeyStore{
+		signing:      signing,
+		verification: make(map[string]*SigningKey),
+		hmacSecret:   []byte(hmacSecret),
+		hmacUntil:    hmacUntil,
+	}
+	if signing != nil {
+		ks.verification[signing.ID] = signing
+	}
+	for _, key := range verification {
+		ks.verification[key.ID] = key
+	}
+	return ks, nil
+}
+
+// LoadKeyStore reads PEM keys from disk. signingFile must hold a private key;
+// verificationFiles may hold public or private keys.
+func LoadKeyStore(signingFile string, verificationFiles []string, hmacSecret string, hmacUntil time.Time) (*KeyStore, error) {
+	var signing *SigningKey
+	if signingFile != "" {
+		key, err := LoadPEMKey(signingFile)
+		if err != nil {
+			return nil, err
+		}
+		signing = key
+	}
+
+	var verification []*SigningKey
+	for _, file := range verificationFiles {
+		if file == "" {
+			continue
+		}
+		key, err := LoadPEMKey(file)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+
+	return NewKeyStore(signing, verification, hmacSecret, hmacUntil)
+}
+
+// LoadPEMKey reads a single PEM encoded Ed25519 or RSA key from disk
+func LoadPEMKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+	key, err := ParsePEMKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParsePEMKey parses PKCS#8, PKCS#1 or PKIX encoded Ed25519 and RSA keys
+func ParsePEMKey(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{}
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private, key.Public = AlgEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Algorithm, key.Public = AlgEdDSA, k
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private, key.Public = AlgRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Algorithm, key.Public = AlgRS256, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	if pub, ok := key.Public.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+	}
+
+	key.ID = key.jwk().thumbprint()
+	return key, nil
+}
+
+// JWKS returns the public keys accepted for verification, signing key first
+func (ks *KeyStore) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range ks.verification {
+		set.Keys = append(set.Keys, key.jwk())
+	}
+	slices.SortFunc(set.Keys, func(a, b JWK) int {
+		if ks.signing != nil && (a.Kid == ks.signing.ID) != (b.Kid == ks.signing.ID) {
+			if a.Kid == ks.signing.ID {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Kid, b.Kid)
+	})
+	return set
+}
+
+func (ks *KeyStore) signingMethod() jwt.SigningMethod {
+	if ks.signing == nil {
+		return jwt.SigningMethodHS256
+	}
+	if ks.signing.Algorithm == AlgRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// sign signs the claims with the active key and sets the kid header
+func (ks *KeyStore) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signingMethod(), claims)
+	if ks.signing == nil {
+		return token.SignedString(ks.hmacSecret)
+	}
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.Private)
+}
+
+// keyFunc selects the verification key by kid and pins its algorithm
+func (ks *KeyStore) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok && ks.acceptsHMAC() {
+			ks.warnHMAC()
+			return ks.hmacSecret, nil
+		}
+		return nil, errors.New("missing kid header")
+	}
+
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.Public, nil
+}
+
+// validMethods lists the algorithms the parser accepts
+func (ks *KeyStore) validMethods() []string {
+	methods := []string{}
+	if ks.acceptsHMAC() {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	seen := map[string]bool{}
+	for _, key := range ks.verification {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			methods = append(methods, key.Algorithm)
+		}
+	}
+	return methods
+}
+
+// acceptsHMAC reports whether HS256 tokens are valid: always when the secret
+// is the only key, and until the migration deadline next to a signing key
+func (ks *KeyStore) acceptsHMAC() bool {
+	if len(ks.hmacSecret) == 0 {
+		return false
+	}
+	return ks.signing == nil || time.Now().Before(ks.hmacUntil)
+}
+
+// warnHMAC logs, at most every hmacWarnInterval, that an HS256 token was
+// accepted during the migration
+func (ks *KeyStore) warnHMAC() {
+	if ks.signing == nil {
+		return
+	}
+	now := time.Now()
+	last := ks.hmacWarnedAt.Load()
+	if now.UnixNano()-last < int64(hmacWarnInterval) || !ks.hmacWarnedAt.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	logger.Warn("Accepted an HS256 token signed with JWT_SECRET; it stops being valid at the migration deadline", "until", ks.hmacUntil)
+}
+
+func (k *SigningKey) jwk() JWK {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Algorithm}
+	switch pub := k.Public.(type) {
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	}
+	return jwk
+}
+
+// PublicKey decodes a JWK published by another issuer. Ed25519, RSA and
+// P-256/P-384 EC keys are supported.
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch j.Kty {
+	case "OKP":
+		x, err := decode(j.X)
+		if err != nil || j.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 JWK")
+		}
+		return ed25519.PublicKey(x), nil
+	case "RSA":
+		n, errN := decode(j.N)
+		e, errE := decode(j.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA JWK")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, errX := decode(j.X)
+		y, errY := decode(j.Y)
+		if errX != nil || errY != nil {
+			return nil, errors.New("invalid EC JWK")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("EC JWK point is not on the curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+	}
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint used as kid
+func (j JWK) thumbprint() string {
+	// Required members only, in lexicographic order
+	var members any
+	switch j.Kty {
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Crv, j.Kty, j.X}
+	default:
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.Kty, j.N}
+	}
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -3,10 +3,10 @@ package utils
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/othersidedrl/portfolio/backend/internal/config"
 	"github.com/othersidedrl/portfolio/backend/internal/logger"
+	"github.com/othersidedrl/portfolio/backend/internal/retry"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -15,16 +15,35 @@ var (
 	RedisCtx    = context.Background()
 )
 
-func InitRedis(cfg *config.Config) {
+// InitRedis creates RedisClient and waits, retrying with backoff, until Redis
+// answers a ping. If it never does the error is returned. Later outages need
+// nothing from the caller: the client reconnects once Redis is reachable.
+func InitRedis(ctx context.Context, cfg *config.Config) error {
 	RedisClient = redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
-		Password: cfg.RedisPassword,
-		DB:       0,
+		Addr:         fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
+		Password:     cfg.RedisPassword,
+		DB:           0,
+		PoolSize:     cfg.RedisPoolSize,
+		MinIdleConns: cfg.RedisMinIdleConns,
+		DialTimeout:  cfg.RedisDialTimeout,
+		ReadTimeout:  cfg.RedisReadTimeout,
+		WriteTimeout: cfg.RedisWriteTimeout,
 	})
 
-	if err := RedisClient.Ping(RedisCtx).Err(); err != nil {
-		logger.Error("Failed to connect to Redis", "error", err)
-		os.Exit(1)
+	err := retry.Do(ctx, cfg.ConnectRetry(), "connect to Redis", func(ctx context.Context) error {
+		return RedisClient.Ping(ctx).Err()
+	})
+	if err != nil {
+		return err
 	}
 	logger.Info("Redis connected")
+	return nil
+}
+
+// CloseRedis closes the connection pool of RedisClient
+func CloseRedis() error {
+	if RedisClient == nil {
+		return nil
+	}
+	return RedisClient.Close()
 }
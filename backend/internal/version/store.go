@@ -0,0 +1,168 @@
+// Package version derives resource versions for entity tags from the
+// updated_at column every content table keeps, and re-checks them when the
+// write they guard runs
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/othersidedrl/portfolio/backend/internal/middleware"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stamp is the part of a row its version is derived from
+type stamp struct {
+	ID        uint
+	UpdatedAt time.Time
+}
+
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Page versions the single row of model's table, like the hero page
+func (s *Store) Page(model interface{}) middleware.VersionFunc {
+	return func(r *http.Request) (string, bool, error) {
+		stamps, err := s.stamps(r, model, s.db.Limit(1))
+		if err != nil || len(stamps) == 0 {
+			return "", false, err
+		}
+		return rowVersion(stamps[0]), true, nil
+	}
+}
+
+// Item versions the live row whose id is the {id} URL parameter
+func (s *Store) Item(model interface{}) middleware.VersionFunc {
+	return s.Of(model, func(r *http.Request) (uint, bool, error) {
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		return uint(id), err == nil, nil
+	})
+}
+
+// Of versions the live row id resolves the request to, for routes that
+// address it through another resource, like a rollback naming a revision
+func (s *Store) Of(model interface{}, id func(r *http.Request) (uint, bool, error)) middleware.VersionFunc {
+	return func(r *http.Request) (string, bool, error) {
+		id, found, err := id(r)
+		if err != nil || !found {
+			return "", false, err
+		}
+		stamps, err := s.stamps(r, model, s.db.Where("id = ?", id))
+		if err != nil || len(stamps) == 0 {
+			return "", false, err
+		}
+		return rowVersion(stamps[0]), true, nil
+	}
+}
+
+// List versions every live row of model's table at once, so it changes when
+// any of them is created, edited or deleted
+func (s *Store) List(model interface{}) middleware.VersionFunc {
+	return func(r *http.Request) (string, bool, error) {
+		stamps, err := s.stamps(r, model, s.db)
+		if err != nil {
+			return "", false, err
+		}
+		h := sha256.New()
+		for _, st := range stamps {
+			h.Write([]byte(strconv.FormatUint(uint64(st.ID), 10) + ":" + rowVersion(st) + ";"))
+		}
+		return hex.EncodeToString(h.Sum(nil)[:12]), true, nil
+	}
+}
+
+// Entries versions an append-only table like revisions, whose rows never
+// change and have no updated_at, by how many rows it has and the newest
+func (s *Store) Entries(model interface{}) middleware.VersionFunc {
+	return func(r *http.Request) (string, bool, error) {
+		var log struct {
+			Count int64
+			MaxID uint
+		}
+		err := s.db.WithContext(r.Context()).Model(model).
+			Select("COUNT(*) AS count, COALESCE(MAX(id), 0) AS max_id").
+			Scan(&log).Error
+		if err != nil {
+			return "", false, err
+		}
+		return strconv.FormatInt(log.Count, 36) + "." + strconv.FormatUint(uint64(log.MaxID), 36), true, nil
+	}
+}
+
+// Entry versions the row of an append-only table whose id is the {id} URL
+// parameter; it never changes, so its id is its version
+func (s *Store) Entry(model interface{}) middleware.VersionFunc {
+	return func(r *http.Request) (string, bool, error) {
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			return "", false, nil
+		}
+		var count int64
+		if err := s.db.WithContext(r.Context()).Model(model).Where("id = ?", id).Count(&count).Error; err != nil || count == 0 {
+			return "", false, err
+		}
+		return strconv.FormatUint(id, 36), true, nil
+	}
+}
+
+// LockPage re-checks, in the write transaction tx, the version IfMatch
+// checked the request against, and locks the page row until tx ends so no
+// other write can slip in before this one. It returns
+// middleware.ErrPreconditionFailed if the row changed in between, and does
+// nothing for writes not guarded by IfMatch.
+func LockPage(ctx context.Context, tx *gorm.DB, model interface{}) error {
+	return lock(ctx, tx.Limit(1), model)
+}
+
+// LockItem is LockPage for the live row with id
+func LockItem(ctx context.Context, tx *gorm.DB, model interface{}, id uint) error {
+	return lock(ctx, tx.Where("id = ?", id), model)
+}
+
+func lock(ctx context.Context, query *gorm.DB, model interface{}) error {
+	want, ok := middleware.ClaimMatchedVersion(ctx)
+	if !ok {
+		return nil
+	}
+	// SQLite has no row locks, but runs one write transaction at a time
+	var st stamp
+	err := query.Clauses(clause.Locking{Strength: "UPDATE"}).Model(model).
+		Select("id", "updated_at").
+		Order("id").
+		Take(&st).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return middleware.ErrPreconditionFailed
+	}
+	if err != nil {
+		return err
+	}
+	if rowVersion(st) != want {
+		return middleware.ErrPreconditionFailed
+	}
+	return nil
+}
+
+func (s *Store) stamps(r *http.Request, model interface{}, query *gorm.DB) ([]stamp, error) {
+	var stamps []stamp
+	err := query.WithContext(r.Context()).Model(model).
+		Select("id", "updated_at").
+		Order("id").
+		Find(&stamps).Error
+	return stamps, err
+}
+
+func rowVersion(st stamp) string {
+	return strconv.FormatInt(st.UpdatedAt.UnixNano(), 36)
+}
@@ -110,7 +110,9 @@ const CareerForm = () => {
     }
   };
 
-  const handleEdit = (item: CareerItem) => {
+  const handleEdit = async ({ id }: CareerItem) => {
+    // Edit the stored version, whose ETag guards the save
+    const { data: item } = await axios.get<CareerItem>(`admin/about/careers/${id}`);
     setForm({
       started_at: item.started_at,
       ended_at: item.ended_at === "Present" ? "" : item.ended_at,
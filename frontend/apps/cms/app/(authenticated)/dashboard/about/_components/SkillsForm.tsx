@@ -196,15 +196,19 @@ const SkillsForm = () => {
                       variant="ghost"
                       size="icon"
                       className="h-7 w-7"
-                      onClick={() => {
+                      onClick={async () => {
+                        // Edit the stored version, whose ETag guards the save
+                        const { data: current } = await axios.get<TechnicalSkill>(
+                          `admin/about/skills/${skill.id}`,
+                        );
                         setEditId(skill.id);
                         setForm({
-                          name: skill.name,
-                          description: skill.description,
-                          specialities: [...skill.specialities],
-                          level: skill.level,
-                          category: skill.category,
-                          year_of_experience: skill.year_of_experience,
+                          name: current.name,
+                          description: current.description,
+                          specialities: [...(current.specialities ?? [])],
+                          level: current.level,
+                          category: current.category,
+                          year_of_experience: current.year_of_experience,
                         });
                         setOpen(true);
                       }}
@@ -189,7 +189,9 @@ const ProjectItems = () => {
     }
   };
 
-  const handleEdit = (item: ProjectItem) => {
+  const handleEdit = async ({ id }: ProjectItem) => {
+    // Edit the stored version, whose ETag guards the save
+    const { data: item } = await axios.get<ProjectItem>(`/admin/project/items/${id}`);
     setForm(item);
     setIsEditing(true);
     setOpen(true);
@@ -0,0 +1,57 @@
+"use client";
+
+import { useRouter } from "next/navigation";
+import { useEffect, useRef } from "react";
+import { toast } from "sonner";
+import axios from "~lib/axios";
+
+const ERRORS: Record<string, string> = {
+  not_allowed: "This account is not allowed to sign in",
+  invalid_state: "The sign-in link expired, please try again",
+};
+
+// Receives the redirect from the identity provider callback and exchanges
+// the one-time ticket for a cookie session
+export default function OIDCCallbackPage() {
+  const router = useRouter();
+  const exchanged = useRef(false);
+
+  useEffect(() => {
+    if (exchanged.current) return;
+    exchanged.current = true;
+
+    const params = new URLSearchParams(window.location.search);
+    const ticket = params.get("ticket");
+    const error = params.get("error");
+
+    if (!ticket) {
+      toast.error(ERRORS[error ?? ""] ?? "Sign-in failed");
+      router.replace("/login");
+      return;
+    }
+
+    axios
+      .post("/auth/oidc/exchange", { ticket, session_cookie: true })
+      .then((res) => {
+        if (res.data.mfa_required) {
+          router.replace(`/login?challenge=${encodeURIComponent(res.data.challenge_token)}`);
+          return;
+        }
+        localStorage.removeItem("token");
+        localStorage.removeItem("refresh_token");
+        localStorage.setItem("csrf_token", res.data.csrf_token);
+        router.replace("/dashboard");
+      })
+      .catch((err) => {
+        console.error(err);
+        toast.error("Sign-in failed");
+        router.replace("/login");
+      });
+  }, [router]);
+
+  return (
+    <main className="min-h-screen flex items-center justify-center bg-[var(--bg-dark)] text-[var(--text-muted)]">
+      Signing in...
+    </main>
+  );
+}
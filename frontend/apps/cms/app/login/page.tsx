@@ -1,34 +1,98 @@
 "use client";
 
-import { useMutation } from "@tanstack/react-query";
+import { useMutation, useQuery } from "@tanstack/react-query";
 import { useRouter } from "next/navigation";
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { toast } from "sonner";
 import axios from "~lib/axios";
 
 export default function LoginPage() {
   const [email, setEmail] = useState("");
   const [password, setPassword] = useState("");
+  const [challengeToken, setChallengeToken] = useState<string | null>(null);
+  const [code, setCode] = useState("");
   const router = useRouter();
 
+  // External logins needing a second factor come back with a challenge
+  useEffect(() => {
+    const challenge = new URLSearchParams(window.location.search).get("challenge");
+    if (challenge) setChallengeToken(challenge);
+  }, []);
+
+  const { data: providers = [] } = useQuery<string[]>({
+    queryKey: ["oidc-providers"],
+    queryFn: async () => {
+      const res = await axios.get("/auth/oidc");
+      return res.data.data;
+    },
+  });
+
+  // biome-ignore lint: any
+  const completeLogin = (data: any) => {
+    // Tokens stay in HttpOnly cookies; only the CSRF token is kept here
+    localStorage.removeItem("token");
+    localStorage.removeItem("refresh_token");
+    localStorage.setItem("csrf_token", data.csrf_token);
+    router.push("/dashboard");
+  };
+
   const loginMutation = useMutation({
     mutationFn: async () => {
-      const res = await axios.post("/auth/login", { email, password });
+      const res = await axios.post("/auth/login", {
+        email,
+        password,
+        session_cookie: true,
+      });
       return res.data;
     },
     onSuccess: (data) => {
-      localStorage.setItem("token", data.token);
-      router.push("/dashboard");
+      if (data.mfa_required) {
+        setChallengeToken(data.challenge_token);
+        return;
+      }
+      completeLogin(data);
+    },
+    // biome-ignore lint: any
+    onError: (err: any) => {
+      toast.error(
+        err?.response?.status === 429
+          ? err.response.data?.error ?? "Too many attempts, try again later"
+          : "Wrong credentials!"
+      );
+      console.error(err);
+    },
+  });
+
+  const verifyMutation = useMutation({
+    mutationFn: async () => {
+      // Authenticator codes are digits only; anything else is a recovery code
+      const isTotp = /^\d{6}$/.test(code.trim());
+      const res = await axios.post("/auth/login/verify", {
+        challenge_token: challengeToken,
+        code: isTotp ? code.trim() : "",
+        recovery_code: isTotp ? "" : code.trim(),
+        session_cookie: true,
+      });
+      return res.data;
     },
+    onSuccess: completeLogin,
     // biome-ignore lint: any
     onError: (err: any) => {
-      toast.error("Wrong credentials!");
+      toast.error(
+        err?.response?.status === 429
+          ? err.response.data?.error ?? "Too many attempts, try again later"
+          : "Invalid code!"
+      );
       console.error(err);
     },
   });
 
   const handleLogin = (e: React.FormEvent) => {
     e.preventDefault();
+    if (challengeToken) {
+      verifyMutation.mutate();
+      return;
+    }
     loginMutation.mutate();
   };
 
@@ -40,41 +104,75 @@ export default function LoginPage() {
       >
         <h1 className="text-2xl font-semibold text-center">CMS Login</h1>
 
-        <div className="space-y-2">
-          <label htmlFor="email" className="block text-sm text-[var(--text-muted)]">
-            Email
-          </label>
-          <input
-            id="email"
-            type="email"
-            value={email}
-            onChange={(e) => setEmail(e.target.value)}
-            className="w-full p-2 rounded bg-[var(--bg-light)] text-[var(--text-strong)] border border-[var(--border-color)] outline-none focus:ring-2 focus:ring-[var(--color-primary)]"
-            required
-          />
-        </div>
+        {challengeToken ? (
+          <div className="space-y-2">
+            <label htmlFor="code" className="block text-sm text-[var(--text-muted)]">
+              Authenticator or recovery code
+            </label>
+            <input
+              id="code"
+              type="text"
+              autoComplete="one-time-code"
+              value={code}
+              onChange={(e) => setCode(e.target.value)}
+              className="w-full p-2 rounded bg-[var(--bg-light)] text-[var(--text-strong)] border border-[var(--border-color)] outline-none focus:ring-2 focus:ring-[var(--color-primary)]"
+              required
+            />
+          </div>
+        ) : (
+          <>
+            <div className="space-y-2">
+              <label htmlFor="email" className="block text-sm text-[var(--text-muted)]">
+                Email
+              </label>
+              <input
+                id="email"
+                type="email"
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                className="w-full p-2 rounded bg-[var(--bg-light)] text-[var(--text-strong)] border border-[var(--border-color)] outline-none focus:ring-2 focus:ring-[var(--color-primary)]"
+                required
+              />
+            </div>
 
-        <div className="space-y-2">
-          <label htmlFor="password" className="block text-sm text-[var(--text-muted)]">
-            Password
-          </label>
-          <input
-            id="password"
-            type="password"
-            value={password}
-            onChange={(e) => setPassword(e.target.value)}
-            className="w-full p-2 rounded bg-[var(--bg-light)] text-[var(--text-strong)] border border-[var(--border-color)] outline-none focus:ring-2 focus:ring-[var(--color-primary)]"
-            required
-          />
-        </div>
+            <div className="space-y-2">
+              <label htmlFor="password" className="block text-sm text-[var(--text-muted)]">
+                Password
+              </label>
+              <input
+                id="password"
+                type="password"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                className="w-full p-2 rounded bg-[var(--bg-light)] text-[var(--text-strong)] border border-[var(--border-color)] outline-none focus:ring-2 focus:ring-[var(--color-primary)]"
+                required
+              />
+            </div>
+          </>
+        )}
 
         <button
           type="submit"
           className="w-full py-2 bg-[var(--color-primary)] text-[var(--color-on-primary)] font-medium rounded hover:opacity-90 transition"
-          disabled={loginMutation.isPending}
+          disabled={loginMutation.isPending || verifyMutation.isPending}
         >
-          {loginMutation.isPending ? "Signing In..." : "Sign In"}
+          {loginMutation.isPending || verifyMutation.isPending
+            ? "Signing In..."
+            : challengeToken
+              ? "Verify"
+              : "Sign In"}
         </button>
+
+        {!challengeToken &&
+          providers.map((provider) => (
+            <a
+              key={provider}
+              href={`${process.env.NEXT_PUBLIC_API_BASE_URL}/auth/oidc/${provider}/login`}
+              className="block w-full py-2 text-center border border-[var(--border-color)] rounded hover:bg-[var(--bg-light)] transition capitalize"
+            >
+              Sign in with {provider}
+            </a>
+          ))}
       </form>
     </main>
   );
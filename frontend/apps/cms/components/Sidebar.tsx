@@ -4,6 +4,7 @@ import { Briefcase, LayoutDashboard, LogOut, MessageCircle, PlusCircle, User } f
 import Link from "next/link";
 import { usePathname } from "next/navigation";
 import { TbExternalLink, TbRocket } from "react-icons/tb";
+import axios from "~lib/axios";
 import { cn } from "~/lib/utils";
 
 const navItems = [
@@ -17,8 +18,15 @@ const navItems = [
 export default function Sidebar() {
   const pathname = usePathname();
 
-  const handleLogout = () => {
+  const handleLogout = async () => {
+    try {
+      await axios.post("/auth/logout", {});
+    } catch {
+      // The session is cleared locally either way
+    }
     localStorage.removeItem("token");
+    localStorage.removeItem("refresh_token");
+    localStorage.removeItem("csrf_token");
     window.location.href = "/login";
   };
 
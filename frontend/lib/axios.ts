@@ -1,15 +1,140 @@
-import axios from "axios";
+import axios, { type AxiosError, type InternalAxiosRequestConfig } from "axios";
 
 const axiosInstance = axios.create({
   baseURL: process.env.NEXT_PUBLIC_API_BASE_URL,
+  // Sends the session cookies when the CMS logged in with session_cookie
+  withCredentials: true,
 });
 
+const SAFE_METHODS = ["get", "head", "options"];
+
+// The CSRF token is echoed from the login/refresh response for cookie sessions
+const csrfHeaders = (): Record<string, string> => {
+  const csrf = localStorage.getItem("csrf_token");
+  return csrf ? { "X-CSRF-Token": csrf } : {};
+};
+
 axiosInstance.interceptors.request.use((config) => {
-  const token = typeof window !== "undefined" ? localStorage.getItem("token") : null;
+  if (typeof window === "undefined") return config;
+
+  const token = localStorage.getItem("token");
   if (token) {
     config.headers.Authorization = `Bearer ${token}`;
+  } else if (!SAFE_METHODS.includes((config.method ?? "get").toLowerCase())) {
+    config.headers.set(csrfHeaders());
+  }
+  return config;
+});
+
+// ETags of the resources the CMS has read, sent back as If-Match so an edit
+// fails with 412 instead of overwriting someone else's change
+const etags = new Map<string, string>();
+const WRITE_METHODS = ["patch", "put", "delete"];
+
+// Writes like items/5/approve are guarded by the version of items/5
+const resourceKey = (url = "") =>
+  url
+    .split("?")[0]
+    .replace(/^\/+/, "")
+    .replace(/(\/\d+)\/[a-z-]+$/, "$1");
+
+axiosInstance.interceptors.request.use(async (config) => {
+  if (typeof window === "undefined") return config;
+  if (!WRITE_METHODS.includes((config.method ?? "get").toLowerCase())) return config;
+
+  const key = resourceKey(config.url);
+  if (!etags.has(key)) {
+    // Nothing read yet, e.g. a delete straight from a list
+    await axiosInstance.get(key).catch(() => undefined);
   }
+  const etag = etags.get(key);
+  if (etag) config.headers.set("If-Match", etag);
   return config;
 });
 
+axiosInstance.interceptors.response.use(
+  (response) => {
+    const key = resourceKey(response.config.url);
+    const etag = response.headers.etag;
+    if ((response.config.method ?? "get").toLowerCase() === "delete") {
+      etags.delete(key);
+    } else if (etag) {
+      etags.set(key, etag);
+    }
+    return response;
+  },
+  (error: AxiosError<{ error?: string }>) => {
+    if (error.response?.status === 412) {
+      etags.delete(resourceKey(error.config?.url));
+      error.response.data = {
+        ...error.response.data,
+        error: "Someone else changed this in the meantime. Reload to see their version.",
+      };
+    }
+    return Promise.reject(error);
+  },
+);
+
+type RetriableRequestConfig = InternalAxiosRequestConfig & { _retried?: boolean };
+
+// Shared so concurrent 401s only trigger a single refresh
+let refreshing: Promise<string | null> | null = null;
+
+// Resolves to the new bearer token, "" for a refreshed cookie session, or
+// null when the session is gone
+const refreshAccessToken = async (): Promise<string | null> => {
+  const refreshToken = localStorage.getItem("refresh_token");
+  const cookieSession = !refreshToken && localStorage.getItem("csrf_token");
+  if (!refreshToken && !cookieSession) return null;
+
+  try {
+    const res = await axios.post(
+      `${process.env.NEXT_PUBLIC_API_BASE_URL}/auth/refresh`,
+      refreshToken ? { refresh_token: refreshToken } : {},
+      {
+        headers: { "Content-Type": "application/json", ...csrfHeaders() },
+        withCredentials: true,
+      },
+    );
+    if (cookieSession) {
+      localStorage.setItem("csrf_token", res.data.csrf_token);
+      return "";
+    }
+    localStorage.setItem("token", res.data.token);
+    localStorage.setItem("refresh_token", res.data.refresh_token);
+    return res.data.token;
+  } catch {
+    localStorage.removeItem("token");
+    localStorage.removeItem("refresh_token");
+    localStorage.removeItem("csrf_token");
+    return null;
+  }
+};
+
+axiosInstance.interceptors.response.use(
+  (response) => response,
+  async (error: AxiosError) => {
+    const original = error.config as RetriableRequestConfig | undefined;
+    if (
+      typeof window === "undefined" ||
+      !original ||
+      original._retried ||
+      error.response?.status !== 401
+    ) {
+      return Promise.reject(error);
+    }
+
+    original._retried = true;
+    refreshing ??= refreshAccessToken().finally(() => {
+      refreshing = null;
+    });
+
+    const token = await refreshing;
+    if (token === null) return Promise.reject(error);
+
+    if (token) original.headers.Authorization = `Bearer ${token}`;
+    return axiosInstance(original);
+  },
+);
+
 export default axiosInstance;